@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/egeuysall/summit/internal/api"
+	"github.com/egeuysall/summit/internal/events"
+	"github.com/egeuysall/summit/internal/jobs"
+	"github.com/egeuysall/summit/internal/mailer"
+	"github.com/egeuysall/summit/internal/services"
 	supabase "github.com/egeuysall/summit/internal/supabase"
 	generated "github.com/egeuysall/summit/internal/supabase/generated"
 	"github.com/egeuysall/summit/internal/utils"
+	"github.com/egeuysall/summit/internal/webhooks"
 	"github.com/joho/godotenv"
 )
 
@@ -22,13 +29,92 @@ func main() {
 	defer dbConn.Close()
 
 	utils.Init(generated.New(dbConn))
+	services.Init(dbConn)
+	events.Init(events.NewMemoryBroker(1000))
+
+	if report, err := services.Reconcile(context.Background()); err != nil {
+		log.Printf("Ledger reconciliation failed: %v", err)
+	} else if !report.Balanced() {
+		log.Printf("Ledger out of balance: %d profile mismatches, %d unbalanced entries, %d escrow mismatches",
+			len(report.BalanceMismatches), len(report.UnbalancedEntries), len(report.EscrowMismatches))
+	} else {
+		log.Printf("Ledger balanced: %d credits held in escrow", report.EscrowTotal)
+	}
+
+	go jobs.Every(context.Background(), 10*time.Minute, "auto-confirm",
+		jobs.AutoConfirm(durationFromEnv("AUTO_CONFIRM_AFTER", 72*time.Hour)))
+	go jobs.Every(context.Background(), time.Minute, "expire-overdue", jobs.ExpireOverdue())
+	go jobs.Every(context.Background(), 15*time.Second, "deliver-webhooks",
+		jobs.DeliverWebhooks(webhooks.NewClient(10*time.Second, webhooks.AllowPrivate())))
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		log.Fatal("PORT not set in environment")
 	}
 
+	mail := mailerFromEnv()
+	links := jobs.EmailLinks{
+		AppURL: envOr("APP_URL", "http://localhost:3000"),
+		APIURL: envOr("API_URL", "http://localhost:"+port),
+	}
+	go jobs.Every(context.Background(), time.Minute, "notification-emails", jobs.SendNotificationEmails(mail, links))
+	go jobs.Every(context.Background(), time.Hour, "email-digests", jobs.SendDigests(mail, links))
+
 	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), api.Router()); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// mailerFromEnv builds the mailer named by MAILER: "smtp", configured by the
+// SMTP_* variables, or "log" (the default), which writes messages to
+// MAIL_LOG_FILE or stdout for local runs.
+func mailerFromEnv() mailer.Mailer {
+	from := envOr("MAIL_FROM", "Summit <no-reply@localhost>")
+
+	switch kind := envOr("MAILER", "log"); kind {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			log.Fatal("SMTP_HOST not set in environment")
+		}
+		return mailer.NewSMTP(host, envOr("SMTP_PORT", "587"),
+			os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	case "log":
+		path := os.Getenv("MAIL_LOG_FILE")
+		if path == "" {
+			return mailer.NewLog(os.Stdout, from)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("Failed to open MAIL_LOG_FILE: %v", err)
+		}
+		return mailer.NewLog(file, from)
+	default:
+		log.Fatalf("MAILER must be smtp or log: %q", kind)
+		return nil
+	}
+}
+
+// envOr returns the environment variable key, or fallback when it is unset.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// durationFromEnv parses a duration such as "72h" from the environment,
+// falling back when the variable is unset.
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("%s must be a positive duration: %q", key, value)
+	}
+
+	return d
+}
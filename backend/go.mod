@@ -3,6 +3,7 @@ module github.com/egeuysall/summit
 go 1.25.2
 
 require (
+	github.com/coder/websocket v1.8.14
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-chi/cors v1.2.2
 	github.com/go-chi/httprate v0.15.0
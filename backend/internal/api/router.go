@@ -16,7 +16,6 @@ func Router() *chi.Mux {
 	r.Use(
 		middleware.Recoverer,
 		middleware.RealIP,
-		middleware.Timeout(30*time.Second),
 		middleware.NoCache,
 		middleware.Compress(5),
 		httprate.LimitByIP(100, time.Minute),
@@ -28,30 +27,77 @@ func Router() *chi.Mux {
 	r.Get("/ping", handlers.HandlePing)
 
 	r.Route("/v1", func(r chi.Router) {
-		// Public routes
-		r.Get("/leaderboard", handlers.GetLeaderboard)
-		r.Get("/rewards", handlers.ListRewards)
-		r.Get("/tasks", handlers.ListTasks)
-		r.Get("/tasks/{taskID}", handlers.GetTask)
+		// Streams and sockets stay open, so they are exempt from the request timeout
+		r.With(appmid.OptionalAuth()).Get("/stream", handlers.Stream)
+		r.With(appmid.TokenFromQuery(), appmid.RequireAuth()).Get("/tasks/{taskID}/messages/ws", handlers.TaskMessagesSocket)
 
-		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(appmid.RequireAuth())
-
-			r.Get("/profile", handlers.GetProfile)
-			r.Post("/profile", handlers.CreateProfile)
-			r.Put("/profile", handlers.UpdateProfile)
-
-			r.Post("/tasks", handlers.CreateTask)
-			r.Get("/tasks/my-posted", handlers.GetMyPostedTasks)
-			r.Get("/tasks/my-claimed", handlers.GetMyClaimedTasks)
-			r.Delete("/tasks/{taskID}", handlers.DeleteTask)
-			r.Post("/tasks/{taskID}/claim", handlers.ClaimTask)
-			r.Post("/tasks/{taskID}/complete", handlers.CompleteTask)
-			r.Post("/tasks/{taskID}/confirm", handlers.ConfirmTask)
-			r.Post("/tasks/{taskID}/cancel", handlers.CancelTask)
-
-			r.Get("/transactions", handlers.GetMyTransactions)
+			r.Use(middleware.Timeout(30 * time.Second))
+
+			// Public routes
+			r.Get("/email/unsubscribe", handlers.UnsubscribeEmailPage)
+			r.Post("/email/unsubscribe", handlers.UnsubscribeEmail)
+			r.With(appmid.OptionalAuth()).Get("/leaderboard", handlers.GetLeaderboard)
+			r.Get("/rewards", handlers.ListRewards)
+			r.Get("/skills", handlers.ListSkills)
+			r.Get("/tasks", handlers.ListTasks)
+			r.Get("/tasks/{taskID}", handlers.GetTask)
+			r.Get("/tasks/{taskID}/history", handlers.GetTaskHistory)
+			r.With(appmid.OptionalAuth()).Get("/users/{userID}", handlers.GetUser)
+			r.Get("/users/{userID}/reviews", handlers.GetUserReviews)
+
+			// Protected routes
+			r.Group(func(r chi.Router) {
+				r.Use(appmid.RequireAuth())
+
+				r.Get("/profile", handlers.GetProfile)
+				r.Post("/profile", handlers.CreateProfile)
+				r.Put("/profile", handlers.UpdateProfile)
+				r.Get("/profile/notification-preferences", handlers.GetNotificationPreferences)
+				r.Put("/profile/notification-preferences", handlers.UpdateNotificationPreferences)
+
+				r.Post("/tasks", handlers.CreateTask)
+				r.Get("/tasks/my-posted", handlers.GetMyPostedTasks)
+				r.Get("/tasks/my-claimed", handlers.GetMyClaimedTasks)
+				r.Get("/tasks/recommended", handlers.GetRecommendedTasks)
+				r.Delete("/tasks/{taskID}", handlers.DeleteTask)
+				r.Post("/tasks/{taskID}/claim", handlers.ClaimTask)
+				r.Post("/tasks/{taskID}/release", handlers.ReleaseTask)
+				r.Post("/tasks/{taskID}/complete", handlers.CompleteTask)
+				r.Post("/tasks/{taskID}/reject", handlers.RejectTask)
+				r.Post("/tasks/{taskID}/confirm", handlers.ConfirmTask)
+				r.Post("/tasks/{taskID}/cancel", handlers.CancelTask)
+				r.Post("/tasks/{taskID}/dispute", handlers.OpenDispute)
+				r.Post("/tasks/{taskID}/review", handlers.ReviewTask)
+				r.Get("/tasks/{taskID}/messages", handlers.GetTaskMessages)
+				r.Post("/tasks/{taskID}/messages", handlers.PostTaskMessage)
+				r.Post("/tasks/{taskID}/messages/read", handlers.ReadTaskMessages)
+
+				r.Get("/disputes", handlers.GetMyDisputes)
+
+				r.Get("/notifications", handlers.GetNotifications)
+				r.Get("/notifications/unread-count", handlers.GetUnreadNotificationCount)
+				r.Post("/notifications/read-all", handlers.ReadAllNotifications)
+				r.Post("/notifications/{notificationID}/read", handlers.ReadNotification)
+
+				r.Post("/webhooks", handlers.CreateWebhook)
+				r.Get("/webhooks", handlers.GetMyWebhooks)
+				r.Delete("/webhooks/{webhookID}", handlers.DeleteWebhook)
+				r.Get("/webhooks/{webhookID}/deliveries", handlers.GetWebhookDeliveries)
+				r.Post("/webhooks/{webhookID}/deliveries/{deliveryID}/replay", handlers.ReplayWebhookDelivery)
+
+				r.Get("/transactions", handlers.GetMyTransactions)
+
+				r.Post("/rewards/{rewardID}/redeem", handlers.RedeemReward)
+				r.Get("/redemptions", handlers.GetMyRedemptions)
+
+				r.Route("/admin", func(r chi.Router) {
+					r.Use(appmid.RequireAdmin())
+
+					r.Get("/disputes", handlers.ListOpenDisputes)
+					r.Post("/disputes/{disputeID}/resolve", handlers.ResolveDispute)
+				})
+			})
 		})
 	})
 
@@ -0,0 +1,97 @@
+// Package events fans task activity out to live subscribers, such as the
+// SSE stream.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Task event types.
+const (
+	TaskCreated   = "task.created"
+	TaskClaimed   = "task.claimed"
+	TaskReleased  = "task.released"
+	TaskCompleted = "task.completed"
+	TaskRejected  = "task.rejected"
+	TaskConfirmed = "task.confirmed"
+	TaskCancelled = "task.cancelled"
+	TaskExpired   = "task.expired"
+	TaskDisputed  = "task.disputed"
+	TaskResolved  = "task.resolved"
+
+	TaskMessage      = "task.message"
+	TaskMessagesRead = "task.messages_read"
+
+	LedgerTransaction = "ledger.transaction"
+)
+
+// Event is something that happened, as delivered to subscribers. Events with
+// an Audience are private to those user IDs; the rest are public.
+type Event struct {
+	ID        uint64
+	Type      string
+	Data      json.RawMessage
+	Audience  []string
+	CreatedAt time.Time
+}
+
+// VisibleTo reports whether the user may receive the event. Anonymous
+// subscribers pass "" and only see public events.
+func (e Event) VisibleTo(userID string) bool {
+	return len(e.Audience) == 0 || (userID != "" && slices.Contains(e.Audience, userID))
+}
+
+// Broker delivers published events to subscribers. The in-process
+// MemoryBroker only reaches subscribers on the same instance; a broker
+// backed by Postgres LISTEN/NOTIFY can replace it when running several.
+type Broker interface {
+	// Publish assigns the event its ID and delivers it.
+	Publish(ctx context.Context, e Event) error
+
+	// Subscribe streams events until ctx is done. Events after lastID that
+	// are still retained are replayed first; pass 0 for new events only.
+	// The channel is closed when ctx is done or the subscriber falls too
+	// far behind, after which it should resubscribe from its last event.
+	Subscribe(ctx context.Context, lastID uint64) (<-chan Event, error)
+}
+
+var ErrNoBroker = errors.New("no event broker configured")
+
+var broker Broker
+
+// Init sets the broker used by Publish and Subscribe.
+func Init(b Broker) {
+	broker = b
+}
+
+// Publish sends an event through the configured broker. It is a no-op when
+// no broker is configured.
+func Publish(ctx context.Context, eventType string, data any, audience ...string) error {
+	if broker == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return broker.Publish(ctx, Event{
+		Type:      eventType,
+		Data:      payload,
+		Audience:  audience,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Subscribe streams events from the configured broker.
+func Subscribe(ctx context.Context, lastID uint64) (<-chan Event, error) {
+	if broker == nil {
+		return nil, ErrNoBroker
+	}
+	return broker.Subscribe(ctx, lastID)
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// it is dropped.
+const subscriberBuffer = 64
+
+// MemoryBroker is a Broker that delivers events within this process and
+// keeps the most recent ones for subscribers resuming after a disconnect.
+type MemoryBroker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	retain      int
+	subscribers map[chan Event]struct{}
+}
+
+// NewMemoryBroker returns a MemoryBroker that retains the last retain events.
+func NewMemoryBroker(retain int) *MemoryBroker {
+	return &MemoryBroker{
+		retain:      retain,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, e Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e.ID = b.lastID
+
+	b.history = append(b.history, e)
+	if len(b.history) > b.retain {
+		b.history = b.history[len(b.history)-b.retain:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			// Too far behind; closing lets the client resume from its last event
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, lastID uint64) (<-chan Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []Event
+	if lastID > 0 {
+		for _, e := range b.history {
+			if e.ID > lastID {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	ch := make(chan Event, len(missed)+subscriberBuffer)
+	for _, e := range missed {
+		ch <- e
+	}
+	b.subscribers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}()
+
+	return ch, nil
+}
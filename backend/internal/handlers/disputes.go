@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// OpenDispute freezes a claimed or completed task until an admin resolves it.
+func OpenDispute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	taskID, err := utils.ParseUUID(chi.URLParam(r, "taskID"))
+	if err != nil {
+		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		utils.SendError(w, "Reason is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := utils.Queries.GetTask(r.Context(), taskID)
+	if err != nil {
+		utils.SendError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	dispute, err := services.OpenDispute(r.Context(), task, uuid, reason)
+	if err != nil {
+		sendTaskError(w, err, "Failed to open dispute")
+		return
+	}
+
+	utils.SendJson(w, models.ToDisputeResponse(dispute), http.StatusCreated)
+}
+
+// GetMyDisputes retrieves disputes on tasks the authenticated user posted or claimed.
+func GetMyDisputes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	disputes, err := utils.Queries.ListUserDisputes(r.Context(), generated.ListUserDisputesParams{
+		UserID:         uuid,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch disputes", http.StatusInternalServerError)
+		return
+	}
+
+	disputes, next := utils.Paginate(disputes, page, disputeCursor)
+	utils.SendPage(w, models.ToDisputeResponses(disputes), next, http.StatusOK)
+}
+
+// ListOpenDisputes retrieves disputes awaiting an admin decision, oldest first.
+func ListOpenDisputes(w http.ResponseWriter, r *http.Request) {
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	disputes, err := utils.Queries.ListOpenDisputes(r.Context(), generated.ListOpenDisputesParams{
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch disputes", http.StatusInternalServerError)
+		return
+	}
+
+	disputes, next := utils.Paginate(disputes, page, disputeCursor)
+	utils.SendPage(w, models.ToDisputeResponses(disputes), next, http.StatusOK)
+}
+
+// ResolveDispute awards, refunds or splits a disputed task's escrow.
+// Accepts "outcome" (award, refund or split), "claimer_percent" for splits
+// and an optional "note".
+func ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	disputeID, err := utils.ParseUUID(chi.URLParam(r, "disputeID"))
+	if err != nil {
+		utils.SendError(w, "Invalid dispute ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Outcome        string `json:"outcome"`
+		ClaimerPercent int32  `json:"claimer_percent"`
+		Note           string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var claimerPercent int32
+	switch req.Outcome {
+	case services.OutcomeAward:
+		claimerPercent = 100
+	case services.OutcomeRefund:
+		claimerPercent = 0
+	case services.OutcomeSplit:
+		if req.ClaimerPercent <= 0 || req.ClaimerPercent >= 100 {
+			utils.SendError(w, "Claimer percent must be between 1 and 99 for a split", http.StatusBadRequest)
+			return
+		}
+		claimerPercent = req.ClaimerPercent
+	default:
+		utils.SendError(w, "Outcome must be award, refund or split", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := utils.Queries.GetDispute(r.Context(), disputeID)
+	if err != nil {
+		utils.SendError(w, "Dispute not found", http.StatusNotFound)
+		return
+	}
+
+	task, err := utils.Queries.GetTask(r.Context(), dispute.TaskID)
+	if err != nil {
+		utils.SendError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	resolved, err := services.ResolveDispute(r.Context(), dispute, task, uuid, req.Outcome, claimerPercent, strings.TrimSpace(req.Note))
+	if err != nil {
+		sendTaskError(w, err, "Failed to resolve dispute")
+		return
+	}
+
+	utils.SendJson(w, models.ToDisputeResponse(resolved), http.StatusOK)
+}
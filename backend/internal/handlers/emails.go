@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// emailTypeNames describe email types on the unsubscribe page.
+var emailTypeNames = map[string]string{
+	services.EmailTaskClaimed:   "task claimed emails",
+	services.EmailTaskCompleted: "task completed emails",
+	services.EmailDigest:        "the daily task digest",
+}
+
+// unsubscribePage asks the user to confirm with a POST, or tells them it
+// is done.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe from Summit emails</title></head>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; color: #111; line-height: 1.5;">
+{{if .Done}}
+  <p>You have been unsubscribed from {{.What}}.</p>
+{{else}}
+  <p>Stop receiving {{.What}} from Summit?</p>
+  <form method="post" action="{{.Action}}">
+    <button type="submit">Unsubscribe</button>
+  </form>
+{{end}}
+</body>
+</html>
+`))
+
+// UnsubscribeEmailPage shows the page the unsubscribe link in an email opens.
+// It changes nothing, since mail scanners follow links; the user confirms
+// with a POST to UnsubscribeEmail. Accepts the same parameters.
+func UnsubscribeEmailPage(w http.ResponseWriter, r *http.Request) {
+	token, kind, ok := parseUnsubscribe(w, r)
+	if !ok {
+		return
+	}
+
+	query := url.Values{"token": {utils.UUIDToString(token)}}
+	if kind != "" {
+		query.Set("type", kind)
+	}
+	renderUnsubscribePage(w, kind, false, "?"+query.Encode())
+}
+
+// UnsubscribeEmail turns an email type off for the user holding the
+// unsubscribe token from an email, without signing in. Accepts "token" and
+// an optional "type"; without a type every email is turned off. Serves both
+// the confirmation form and one-click unsubscribe (RFC 8058).
+func UnsubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	token, kind, ok := parseUnsubscribe(w, r)
+	if !ok {
+		return
+	}
+
+	kinds := services.EmailTypes
+	if kind != "" {
+		kinds = []string{kind}
+	}
+
+	off := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		off[kind] = false
+	}
+	preferences, _ := json.Marshal(off)
+
+	_, err := utils.Queries.UnsubscribeEmail(r.Context(), generated.UnsubscribeEmailParams{
+		Preferences: preferences,
+		Token:       token,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, "Unsubscribe link not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.SendError(w, "Failed to unsubscribe", http.StatusInternalServerError)
+		return
+	}
+
+	renderUnsubscribePage(w, kind, true, "")
+}
+
+// parseUnsubscribe reads the "token" and "type" query parameters, writing a
+// 400 response and returning false if either is invalid.
+func parseUnsubscribe(w http.ResponseWriter, r *http.Request) (pgtype.UUID, string, bool) {
+	token, err := utils.ParseUUID(r.URL.Query().Get("token"))
+	if err != nil {
+		utils.SendError(w, "Invalid unsubscribe token", http.StatusBadRequest)
+		return token, "", false
+	}
+
+	kind := r.URL.Query().Get("type")
+	if kind != "" && !slices.Contains(services.EmailTypes, kind) {
+		utils.SendError(w, "Unknown email type: "+kind, http.StatusBadRequest)
+		return token, "", false
+	}
+	return token, kind, true
+}
+
+func renderUnsubscribePage(w http.ResponseWriter, kind string, done bool, action string) {
+	what := "all emails"
+	if kind != "" {
+		what = emailTypeNames[kind]
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	unsubscribePage.Execute(w, struct {
+		What   string
+		Done   bool
+		Action string
+	}{what, done, action})
+}
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/egeuysall/summit/internal/events"
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// maxMessageLength is the longest message body, in characters.
+const maxMessageLength = 2000
+
+// socketPing is how often TaskMessagesSocket pings idle clients.
+const socketPing = 30 * time.Second
+
+// socketReadLimit is the largest message a socket client may send, in bytes.
+const socketReadLimit = 64 << 10
+
+var (
+	errEmptyMessage   = errors.New("message body is required")
+	errMessageTooLong = errors.New("message body is too long")
+	errInvalidCommand = errors.New("invalid command")
+)
+
+// GetTaskMessages lists the conversation between a task's requester and
+// claimer, newest first. Accepts "limit" and "cursor" for paging.
+func GetTaskMessages(w http.ResponseWriter, r *http.Request) {
+	task, _, claimerID, ok := loadConversation(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := utils.Queries.ListTaskMessages(r.Context(), generated.ListTaskMessagesParams{
+		TaskID:         task.ID,
+		ClaimerID:      claimerID,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch messages", http.StatusInternalServerError)
+		return
+	}
+
+	messages, next := utils.Paginate(messages, page, messageCursor)
+	utils.SendPage(w, models.ToMessageResponses(messages), next, http.StatusOK)
+}
+
+// PostTaskMessage sends a message to the other party on a task. Accepts
+// "body".
+func PostTaskMessage(w http.ResponseWriter, r *http.Request) {
+	task, uuid, _, ok := loadConversation(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body string `json:"body"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	message, err := sendTaskMessage(r.Context(), task, uuid, req.Body)
+	if err != nil {
+		sendMessageError(w, err)
+		return
+	}
+
+	utils.SendJson(w, message, http.StatusCreated)
+}
+
+// ReadTaskMessages marks the other party's messages as read, up to and
+// including "message_id", and notifies them.
+func ReadTaskMessages(w http.ResponseWriter, r *http.Request) {
+	task, uuid, _, ok := loadConversation(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MessageID string `json:"message_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	messageID, err := utils.ParseUUID(req.MessageID)
+	if err != nil {
+		utils.SendError(w, "Invalid message ID", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := readTaskMessages(r.Context(), task, uuid, messageID)
+	if err != nil {
+		sendMessageError(w, err)
+		return
+	}
+
+	utils.SendJson(w, receipt, http.StatusOK)
+}
+
+// TaskMessagesSocket upgrades to a WebSocket that delivers the task's new
+// messages and read receipts as they happen. Clients send
+// {"type": "message", "body": ...} to post and
+// {"type": "read", "message_id": ...} to mark messages read; the server
+// sends {"type": "task.message" | "task.messages_read" | "error", ...}.
+func TaskMessagesSocket(w http.ResponseWriter, r *http.Request) {
+	task, uuid, _, ok := loadConversation(w, r)
+	if !ok {
+		return
+	}
+	taskID, userID := utils.UUIDToString(task.ID), utils.UUIDToString(uuid)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := events.Subscribe(ctx, 0)
+	if err != nil {
+		utils.SendError(w, "Failed to subscribe to messages", http.StatusInternalServerError)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		OriginPatterns: appmid.AllowedOriginHosts(),
+	})
+	if err != nil {
+		return // Accept has written the response
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(socketReadLimit)
+
+	go func() {
+		defer cancel()
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			if err := handleSocketCommand(ctx, task.ID, uuid, data); err != nil {
+				wsjson.Write(ctx, conn, map[string]string{"type": "error", "error": messageErrorText(err)})
+			}
+		}
+	}()
+
+	ping := time.NewTicker(socketPing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close(websocket.StatusNormalClosure, "")
+			return
+		case <-ping.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, socketPing)
+			err := conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return
+			}
+		case e, ok := <-stream:
+			if !ok {
+				return // Dropped by the broker; the client reconnects and refetches
+			}
+			if !isConversationEvent(e, taskID, userID) {
+				continue
+			}
+			if err := wsjson.Write(ctx, conn, map[string]any{"type": e.Type, "data": e.Data}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleSocketCommand applies one client message from TaskMessagesSocket.
+// The task is reloaded so each command sees its current status and claimer.
+func handleSocketCommand(ctx context.Context, taskID, userID pgtype.UUID, data []byte) error {
+	var cmd struct {
+		Type      string `json:"type"`
+		Body      string `json:"body"`
+		MessageID string `json:"message_id"`
+	}
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return errInvalidCommand
+	}
+
+	task, err := utils.Queries.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Type {
+	case "message":
+		_, err = sendTaskMessage(ctx, task, userID, cmd.Body)
+	case "read":
+		messageID, parseErr := utils.ParseUUID(cmd.MessageID)
+		if parseErr != nil {
+			return errInvalidCommand
+		}
+		_, err = readTaskMessages(ctx, task, userID, messageID)
+	default:
+		return errInvalidCommand
+	}
+	return err
+}
+
+// isConversationEvent reports whether e belongs on userID's socket for taskID.
+func isConversationEvent(e events.Event, taskID, userID string) bool {
+	if e.Type != events.TaskMessage && e.Type != events.TaskMessagesRead {
+		return false
+	}
+	if !e.VisibleTo(userID) {
+		return false
+	}
+
+	var payload struct {
+		TaskID string `json:"task_id"`
+	}
+	return json.Unmarshal(e.Data, &payload) == nil && payload.TaskID == taskID
+}
+
+// sendTaskMessage validates and posts a message, then publishes it to both
+// parties.
+func sendTaskMessage(ctx context.Context, task generated.Task, senderID pgtype.UUID, body string) (models.MessageResponse, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return models.MessageResponse{}, errEmptyMessage
+	}
+	if utf8.RuneCountInString(body) > maxMessageLength {
+		return models.MessageResponse{}, errMessageTooLong
+	}
+
+	message, err := services.SendTaskMessage(ctx, task, senderID, body)
+	if err != nil {
+		return models.MessageResponse{}, err
+	}
+
+	response := models.ToMessageResponse(message)
+	publishConversationEvent(ctx, events.TaskMessage, task, message.ClaimerID, response)
+	return response, nil
+}
+
+// readTaskMessages marks messages read and, if any were unread, publishes a
+// receipt to both parties.
+func readTaskMessages(ctx context.Context, task generated.Task, readerID, messageID pgtype.UUID) (models.ReadReceiptResponse, error) {
+	messages, err := services.MarkTaskMessagesRead(ctx, task, readerID, messageID)
+	if err != nil {
+		return models.ReadReceiptResponse{}, err
+	}
+
+	receipt := models.ToReadReceiptResponse(task.ID, readerID, messages)
+	if len(messages) > 0 {
+		publishConversationEvent(ctx, events.TaskMessagesRead, task, messages[0].ClaimerID, receipt)
+	}
+	return receipt, nil
+}
+
+// publishConversationEvent sends a conversation event to the task's
+// requester and the claimer the conversation is with only.
+func publishConversationEvent(ctx context.Context, eventType string, task generated.Task, claimerID pgtype.UUID, data any) {
+	audience := []string{utils.UUIDToString(task.RequesterID), utils.UUIDToString(claimerID)}
+	if err := events.Publish(ctx, eventType, data, audience...); err != nil {
+		log.Printf("Failed to publish %s for task %s: %v", eventType, utils.UUIDToString(task.ID), err)
+	}
+}
+
+// loadConversation resolves the authenticated user, the task named in the
+// URL and the claimer whose conversation on it the user may read. It writes
+// an error response and returns false if there is none.
+func loadConversation(w http.ResponseWriter, r *http.Request) (generated.Task, pgtype.UUID, pgtype.UUID, bool) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return generated.Task{}, pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return generated.Task{}, pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	taskID, err := utils.ParseUUID(chi.URLParam(r, "taskID"))
+	if err != nil {
+		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
+		return generated.Task{}, pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	task, err := utils.Queries.GetTask(r.Context(), taskID)
+	if err != nil {
+		utils.SendError(w, "Task not found", http.StatusNotFound)
+		return generated.Task{}, pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	claimerID, err := services.ConversationClaimer(r.Context(), task, uuid)
+	if err != nil {
+		sendMessageError(w, err)
+		return generated.Task{}, pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	return task, uuid, claimerID, true
+}
+
+// sendMessageError maps errors from the conversation handlers to HTTP responses.
+func sendMessageError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errEmptyMessage), errors.Is(err, errMessageTooLong):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNotTaskParty):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrNoConversation), errors.Is(err, services.ErrConversationClosed):
+		status = http.StatusConflict
+	}
+
+	utils.SendError(w, messageErrorText(err), status)
+}
+
+// messageErrorText is the client-facing description of a conversation error.
+func messageErrorText(err error) string {
+	switch {
+	case errors.Is(err, errEmptyMessage):
+		return "Message body is required"
+	case errors.Is(err, errMessageTooLong):
+		return "Message body must be at most 2000 characters"
+	case errors.Is(err, errInvalidCommand):
+		return "Invalid command"
+	case errors.Is(err, services.ErrNotTaskParty):
+		return "Only the requester and claimer can message on this task"
+	case errors.Is(err, services.ErrNoConversation):
+		return "Task has not been claimed"
+	case errors.Is(err, services.ErrConversationClosed):
+		return "This conversation is closed"
+	}
+	return "Failed to process message"
+}
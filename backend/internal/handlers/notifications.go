@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// GetNotifications lists the authenticated user's notifications, newest
+// first. Accepts "unread=true" to list only unread ones, plus "limit" and
+// "cursor" for paging.
+func GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	notifications, err := utils.Queries.ListNotifications(r.Context(), generated.ListNotificationsParams{
+		UserID:         uuid,
+		UnreadOnly:     r.URL.Query().Get("unread") == "true",
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch notifications", http.StatusInternalServerError)
+		return
+	}
+
+	notifications, next := utils.Paginate(notifications, page, notificationCursor)
+	utils.SendPage(w, models.ToNotificationResponses(notifications), next, http.StatusOK)
+}
+
+// GetUnreadNotificationCount returns how many notifications the
+// authenticated user has not read.
+func GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	count, err := utils.Queries.CountUnreadNotifications(r.Context(), uuid)
+	if err != nil {
+		utils.SendError(w, "Failed to count notifications", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, map[string]int32{"unread": count}, http.StatusOK)
+}
+
+// ReadNotification marks one of the authenticated user's notifications as read.
+func ReadNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	notificationID, err := utils.ParseUUID(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		utils.SendError(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	notification, err := utils.Queries.MarkNotificationRead(r.Context(), generated.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: uuid,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.SendError(w, "Failed to update notification", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToNotificationResponse(notification), http.StatusOK)
+}
+
+// ReadAllNotifications marks all of the authenticated user's notifications
+// as read.
+func ReadAllNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := utils.Queries.MarkAllNotificationsRead(r.Context(), uuid)
+	if err != nil {
+		utils.SendError(w, "Failed to update notifications", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, map[string]int64{"updated": updated}, http.StatusOK)
+}
+
+// GetNotificationPreferences returns whether each notification and email
+// type is on for the authenticated user.
+func GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := utils.Queries.GetNotificationPreferences(r.Context(), uuid)
+	if err != nil {
+		utils.SendError(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+
+	utils.SendJson(w, notificationPreferences(stored), http.StatusOK)
+}
+
+// UpdateNotificationPreferences turns notification and email types on or
+// off. Accepts an object mapping types to booleans; types left out are
+// unchanged.
+func UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req map[string]bool
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	for kind := range req {
+		if !slices.Contains(services.NotificationTypes, kind) && !slices.Contains(services.EmailTypes, kind) {
+			utils.SendError(w, "Unknown notification type: "+kind, http.StatusBadRequest)
+			return
+		}
+	}
+
+	preferences, _ := json.Marshal(req)
+
+	updated, err := utils.Queries.UpdateNotificationPreferences(r.Context(), generated.UpdateNotificationPreferencesParams{
+		Preferences: preferences,
+		UserID:      uuid,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, "Profile not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.SendError(w, "Failed to update notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, notificationPreferences(updated.Preferences), http.StatusOK)
+}
+
+// notificationPreferences lists every notification and email type with
+// whether it is on, given the stored preferences. Types the user never set
+// are on.
+func notificationPreferences(stored []byte) map[string]bool {
+	var set map[string]bool
+	json.Unmarshal(stored, &set)
+
+	preferences := make(map[string]bool, len(services.NotificationTypes)+len(services.EmailTypes))
+	for _, kind := range slices.Concat(services.NotificationTypes, services.EmailTypes) {
+		on, ok := set[kind]
+		preferences[kind] = on || !ok
+	}
+	return preferences
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// parsePage reads the "limit" and "cursor" query parameters of a list whose
+// rows have UUIDs, writing a 400 response and returning false if either is
+// invalid.
+func parsePage(w http.ResponseWriter, r *http.Request) (utils.Page, bool) {
+	page, err := utils.ParseUUIDPage(r)
+	return page, checkPage(w, err)
+}
+
+// parseKeyedPage is parsePage for lists that parse the cursor's ID
+// themselves, such as rewards, whose IDs are integers.
+func parseKeyedPage(w http.ResponseWriter, r *http.Request) (utils.Page, bool) {
+	page, err := utils.ParsePage(r)
+	return page, checkPage(w, err)
+}
+
+// checkPage writes a 400 response and returns false if parsing a page
+// failed.
+func checkPage(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, utils.ErrInvalidLimit):
+		utils.SendError(w, "Limit must be between 1 and 100", http.StatusBadRequest)
+		return false
+	case err != nil:
+		utils.SendError(w, "Invalid cursor", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// parseLimit reads the "limit" query parameter, defaulting to def, writing a
+// 400 response and returning false if it is invalid.
+func parseLimit(w http.ResponseWriter, r *http.Request, def int32) (int32, bool) {
+	limit, err := utils.ParseLimit(r, def)
+	if err != nil {
+		utils.SendError(w, "Limit must be between 1 and 100", http.StatusBadRequest)
+		return 0, false
+	}
+	return limit, true
+}
+
+func taskCursor(t generated.Task) utils.Cursor {
+	return utils.Cursor{CreatedAt: t.CreatedAt.Time, ID: utils.UUIDToString(t.ID)}
+}
+
+func disputeCursor(d generated.Dispute) utils.Cursor {
+	return utils.Cursor{CreatedAt: d.CreatedAt.Time, ID: utils.UUIDToString(d.ID)}
+}
+
+func transactionCursor(t generated.Transaction) utils.Cursor {
+	return utils.Cursor{CreatedAt: t.CreatedAt.Time, ID: utils.UUIDToString(t.ID)}
+}
+
+func redemptionCursor(r generated.GetUserRedemptionsRow) utils.Cursor {
+	return utils.Cursor{CreatedAt: r.CreatedAt.Time, ID: utils.UUIDToString(r.ID)}
+}
+
+func messageCursor(m generated.TaskMessage) utils.Cursor {
+	return utils.Cursor{CreatedAt: m.CreatedAt.Time, ID: utils.UUIDToString(m.ID)}
+}
+
+func notificationCursor(n generated.Notification) utils.Cursor {
+	return utils.Cursor{CreatedAt: n.CreatedAt.Time, ID: utils.UUIDToString(n.ID)}
+}
+
+func webhookDeliveryCursor(d generated.WebhookDelivery) utils.Cursor {
+	return utils.Cursor{CreatedAt: d.CreatedAt.Time, ID: utils.UUIDToString(d.ID)}
+}
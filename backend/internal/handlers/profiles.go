@@ -3,9 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
+	"time"
 
 	appmid "github.com/egeuysall/summit/internal/middleware"
 	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
 	"github.com/egeuysall/summit/internal/utils"
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -32,7 +35,7 @@ func GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.SendJson(w, models.ToProfileResponse(profile), http.StatusOK)
+	sendProfile(w, r, profile, http.StatusOK)
 }
 
 func CreateProfile(w http.ResponseWriter, r *http.Request) {
@@ -50,7 +53,7 @@ func CreateProfile(w http.ResponseWriter, r *http.Request) {
 
 	existingProfile, err := utils.Queries.GetProfile(r.Context(), uuid)
 	if err == nil {
-		utils.SendJson(w, models.ToProfileResponse(existingProfile), http.StatusOK)
+		sendProfile(w, r, existingProfile, http.StatusOK)
 		return
 	}
 
@@ -70,6 +73,12 @@ func CreateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	skills, err := canonicalSkills(r.Context(), req.Skills)
+	if err != nil {
+		sendSkillError(w, err)
+		return
+	}
+
 	var avatarUrl pgtype.Text
 	if req.AvatarUrl != nil {
 		avatarUrl.String = *req.AvatarUrl
@@ -80,17 +89,16 @@ func CreateProfile(w http.ResponseWriter, r *http.Request) {
 		ID:        uuid,
 		Name:      req.Name,
 		AvatarUrl: avatarUrl,
-		Skills:    req.Skills,
-		Credits:   pgtype.Int4{Int32: 100, Valid: true},
+		Skills:    skills,
 	}
 
-	profile, err := utils.Queries.CreateProfile(r.Context(), params)
+	profile, err := services.CreateProfile(r.Context(), params)
 	if err != nil {
 		utils.SendError(w, "Failed to create profile", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToProfileResponse(profile), http.StatusCreated)
+	sendProfile(w, r, profile, http.StatusCreated)
 }
 
 func UpdateProfile(w http.ResponseWriter, r *http.Request) {
@@ -122,6 +130,12 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	skills, err := canonicalSkills(r.Context(), req.Skills)
+	if err != nil {
+		sendSkillError(w, err)
+		return
+	}
+
 	var avatarUrl pgtype.Text
 	if req.AvatarUrl != nil {
 		avatarUrl.String = *req.AvatarUrl
@@ -132,7 +146,7 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		ID:        uuid,
 		Name:      req.Name,
 		AvatarUrl: avatarUrl,
-		Skills:    req.Skills,
+		Skills:    skills,
 	}
 
 	err = utils.Queries.UpdateProfile(r.Context(), params)
@@ -147,17 +161,105 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.SendJson(w, models.ToProfileResponse(profile), http.StatusOK)
+	sendProfile(w, r, profile, http.StatusOK)
+}
+
+// sendProfile writes a profile along with the user's current reputation.
+func sendProfile(w http.ResponseWriter, r *http.Request, profile generated.Profile, statusCode int) {
+	rep, err := utils.Queries.GetUserReputation(r.Context(), profile.ID)
+	if err != nil {
+		utils.SendError(w, "Failed to fetch reputation", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToProfileResponse(profile, rep), statusCode)
+}
+
+// leaderboardPeriods maps each "period" to how far back it looks. All-time
+// has no window.
+var leaderboardPeriods = map[string]time.Duration{
+	"week":     7 * 24 * time.Hour,
+	"month":    30 * 24 * time.Hour,
+	"all-time": 0,
 }
 
+// GetLeaderboard ranks users. Accepts optional query parameters "metric"
+// (balance, earned or completed; default: balance), "period" (week, month
+// or all-time; default: all-time), "skill" and "limit" (default and max: 100).
+// Period and skill only apply to earned and completed, since a balance has
+// no history. Authenticated callers also get their own entry as "me".
 func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
-	limit := int32(100)
+	query := r.URL.Query()
+
+	params := generated.GetLeaderboardParams{
+		Metric: "balance",
+		Skill:  optionalText(query.Get("skill")),
+	}
+
+	if metric := query.Get("metric"); metric != "" {
+		if !slices.Contains([]string{"balance", "earned", "completed"}, metric) {
+			utils.SendError(w, "Metric must be balance, earned or completed", http.StatusBadRequest)
+			return
+		}
+		params.Metric = metric
+	}
 
-	leaderboard, err := utils.Queries.GetLeaderboard(r.Context(), limit)
+	period := query.Get("period")
+	if period == "" {
+		period = "all-time"
+	}
+	window, ok := leaderboardPeriods[period]
+	if !ok {
+		utils.SendError(w, "Period must be week, month or all-time", http.StatusBadRequest)
+		return
+	}
+	if window > 0 {
+		params.Since = pgtype.Timestamptz{Time: time.Now().Add(-window), Valid: true}
+	}
+
+	if params.Metric == "balance" && (params.Since.Valid || params.Skill.Valid) {
+		utils.SendError(w, "Period and skill require the earned or completed metric", http.StatusBadRequest)
+		return
+	}
+
+	if params.Skill.Valid {
+		skill, err := canonicalSkill(r.Context(), params.Skill.String)
+		if err != nil {
+			sendSkillError(w, err)
+			return
+		}
+		params.Skill.String = skill
+	}
+
+	if params.PageLimit, ok = parseLimit(w, r, utils.MaxPageLimit); !ok {
+		return
+	}
+
+	callerID := ""
+	if userID, ok := appmid.UserIDFromContext(r.Context()); ok {
+		if id, err := utils.ParseUUID(userID); err == nil {
+			params.CallerID = id
+			callerID = userID
+		}
+	}
+
+	rows, err := utils.Queries.GetLeaderboard(r.Context(), params)
 	if err != nil {
 		utils.SendError(w, "Failed to fetch leaderboard", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToLeaderboardResponses(leaderboard), http.StatusOK)
+	// The caller's row is last when they fall outside the top
+	entries := models.ToLeaderboardResponses(rows)
+	var me *models.LeaderboardEntryResponse
+	for i := range entries {
+		if entries[i].ID == callerID {
+			me = &entries[i]
+		}
+	}
+	if int32(len(entries)) > params.PageLimit {
+		entries = entries[:params.PageLimit]
+	}
+
+	utils.SendJsonWithMeta(w, entries, map[string]interface{}{"me": me}, http.StatusOK)
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// ReviewTask rates the other party to a confirmed task. Accepts "rating"
+// (1-5) and "review".
+func ReviewTask(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	taskID, err := utils.ParseUUID(chi.URLParam(r, "taskID"))
+	if err != nil {
+		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Rating int32  `json:"rating"`
+		Review string `json:"review"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Rating < 1 || req.Rating > 5 {
+		utils.SendError(w, "Rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
+
+	body := strings.TrimSpace(req.Review)
+	if body == "" {
+		utils.SendError(w, "Review is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := utils.Queries.GetTask(r.Context(), taskID)
+	if err != nil {
+		utils.SendError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	review, err := services.CreateReview(r.Context(), task, uuid, req.Rating, body)
+	switch {
+	case errors.Is(err, services.ErrNotReviewable):
+		utils.SendError(w, "Only confirmed tasks can be reviewed", http.StatusBadRequest)
+		return
+	case errors.Is(err, services.ErrNotTaskParty):
+		utils.SendError(w, "You are not allowed to review this task", http.StatusForbidden)
+		return
+	case errors.Is(err, services.ErrAlreadyReviewed):
+		utils.SendError(w, "You have already reviewed this task", http.StatusConflict)
+		return
+	case err != nil:
+		utils.SendError(w, "Failed to create review", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToReviewResponse(review), http.StatusCreated)
+}
+
+// GetUserReviews retrieves the reviews a user has received, newest first.
+func GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ParseUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	reviews, err := utils.Queries.ListUserReviews(r.Context(), generated.ListUserReviewsParams{
+		RevieweeID:     userID,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch reviews", http.StatusInternalServerError)
+		return
+	}
+
+	reviews, next := utils.Paginate(reviews, page, func(row generated.ListUserReviewsRow) utils.Cursor {
+		return utils.Cursor{CreatedAt: row.CreatedAt.Time, ID: utils.UUIDToString(row.ID)}
+	})
+	utils.SendPage(w, models.ToUserReviewResponses(reviews), next, http.StatusOK)
+}
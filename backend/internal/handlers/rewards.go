@@ -1,19 +1,119 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
 	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
 	"github.com/egeuysall/summit/internal/utils"
 )
 
-// ListRewards retrieves all available rewards.
+// ListRewards retrieves available rewards, cheapest first.
 func ListRewards(w http.ResponseWriter, r *http.Request) {
-	rewards, err := utils.Queries.ListRewards(r.Context())
+	page, ok := parseKeyedPage(w, r)
+	if !ok {
+		return
+	}
+
+	params := generated.ListRewardsParams{PageLimit: page.FetchLimit()}
+	if page.After != nil {
+		afterID, err := strconv.ParseInt(page.After.ID, 10, 32)
+		if err != nil {
+			utils.SendError(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		params.AfterCost = page.AfterKey()
+		params.AfterID = pgtype.Int4{Int32: int32(afterID), Valid: true}
+	}
+
+	rewards, err := utils.Queries.ListRewards(r.Context(), params)
 	if err != nil {
 		utils.SendError(w, "Failed to fetch rewards", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToRewardResponses(rewards), http.StatusOK)
+	rewards, next := utils.Paginate(rewards, page, func(reward generated.Reward) utils.Cursor {
+		return utils.Cursor{Key: reward.Cost, ID: strconv.Itoa(int(reward.ID))}
+	})
+	utils.SendPage(w, models.ToRewardResponses(rewards), next, http.StatusOK)
+}
+
+// RedeemReward spends the authenticated user's credits on a reward.
+func RedeemReward(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	rewardID, err := strconv.ParseInt(chi.URLParam(r, "rewardID"), 10, 32)
+	if err != nil {
+		utils.SendError(w, "Invalid reward ID", http.StatusBadRequest)
+		return
+	}
+
+	reward, err := utils.Queries.GetReward(r.Context(), int32(rewardID))
+	if err != nil {
+		utils.SendError(w, "Reward not found", http.StatusNotFound)
+		return
+	}
+
+	redemption, err := services.RedeemReward(r.Context(), uuid, reward)
+	if errors.Is(err, services.ErrInsufficientCredits) {
+		utils.SendError(w, "Insufficient credits", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		utils.SendError(w, "Failed to redeem reward", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToRedemptionResponse(redemption, reward), http.StatusCreated)
+}
+
+// GetMyRedemptions retrieves the rewards redeemed by the authenticated user.
+func GetMyRedemptions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	redemptions, err := utils.Queries.GetUserRedemptions(r.Context(), generated.GetUserRedemptionsParams{
+		UserID:         uuid,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch redemptions", http.StatusInternalServerError)
+		return
+	}
+
+	redemptions, next := utils.Paginate(redemptions, page, redemptionCursor)
+	utils.SendPage(w, models.ToUserRedemptionResponses(redemptions), next, http.StatusOK)
 }
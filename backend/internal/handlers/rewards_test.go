@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestListRewardsPages(t *testing.T) {
+	rewards := []generated.Reward{
+		{ID: 3, Name: "Sticker", Cost: 10},
+		{ID: 1, Name: "Mug", Cost: 50},
+		{ID: 4, Name: "Cap", Cost: 50},
+		{ID: 2, Name: "Hoodie", Cost: 200},
+	}
+	utils.Init(generated.New(rewardsDB{rewards: rewards}))
+
+	first := listRewards(t, "?limit=2")
+	if got := rewardNames(first); got != "Sticker,Mug" {
+		t.Fatalf("page 1 = %s, want Sticker,Mug", got)
+	}
+	if first.NextCursor == nil {
+		t.Fatal("page 1 has no next_cursor")
+	}
+
+	second := listRewards(t, "?limit=2&cursor="+url.QueryEscape(*first.NextCursor))
+	if got := rewardNames(second); got != "Cap,Hoodie" {
+		t.Errorf("page 2 = %s, want Cap,Hoodie", got)
+	}
+	if second.NextCursor != nil {
+		t.Errorf("page 2 next_cursor = %q, want null", *second.NextCursor)
+	}
+}
+
+type rewardsPage struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+func listRewards(t *testing.T, query string) rewardsPage {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	ListRewards(w, httptest.NewRequest(http.MethodGet, "/v1/rewards"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /v1/rewards%s = %d: %s", query, w.Code, w.Body)
+	}
+
+	var page rewardsPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decoding page: %v", err)
+	}
+	return page
+}
+
+func rewardNames(page rewardsPage) string {
+	names := make([]string, len(page.Data))
+	for i, reward := range page.Data {
+		names[i] = reward.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// rewardsDB answers ListRewards from rewards, which are sorted by cost and
+// ID as the query orders them.
+type rewardsDB struct {
+	rewards []generated.Reward
+}
+
+func (db rewardsDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if !strings.HasPrefix(sql, "-- name: ListRewards ") {
+		return nil, fmt.Errorf("unexpected query: %.60s", sql)
+	}
+	afterCost, afterID, limit := args[0].(pgtype.Int4), args[1].(pgtype.Int4), args[2].(int32)
+
+	var page []generated.Reward
+	for _, reward := range db.rewards {
+		after := !afterCost.Valid || reward.Cost > afterCost.Int32 ||
+			(reward.Cost == afterCost.Int32 && reward.ID > afterID.Int32)
+		if after && int32(len(page)) < limit {
+			page = append(page, reward)
+		}
+	}
+	return &rewardRows{rows: page}, nil
+}
+
+func (db rewardsDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected exec: %.60s", sql)
+}
+
+func (db rewardsDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	panic("unexpected query: " + sql)
+}
+
+// rewardRows iterates over rewards. The pgx.Rows methods generated code does
+// not call are left to the nil embedded interface.
+type rewardRows struct {
+	pgx.Rows
+	rows []generated.Reward
+	next int
+}
+
+func (r *rewardRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *rewardRows) Scan(dest ...any) error {
+	row := r.rows[r.next-1]
+	*dest[0].(*int32) = row.ID
+	*dest[1].(*string) = row.Name
+	*dest[2].(*string) = row.Planet
+	*dest[3].(*int32) = row.Cost
+	*dest[4].(*pgtype.Text) = row.Description
+	return nil
+}
+
+func (r *rewardRows) Close()     {}
+func (r *rewardRows) Err() error { return nil }
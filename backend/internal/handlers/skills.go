@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/egeuysall/summit/internal/models"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// unknownSkillError reports a skill that matches no slug, name or alias.
+type unknownSkillError struct {
+	name string
+}
+
+func (e *unknownSkillError) Error() string {
+	return fmt.Sprintf("unknown skill %q", e.name)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ListSkills retrieves the skill taxonomy for autocomplete.
+// Accepts optional query parameter "q" to match the start of a skill's slug,
+// name or aliases.
+func ListSkills(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	prefix = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+
+	skills, err := utils.Queries.ListSkills(r.Context(), pgtype.Text{String: prefix, Valid: prefix != ""})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch skills", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToSkillResponses(skills), http.StatusOK)
+}
+
+// canonicalSkill maps a skill as a user typed it ("Golang", "go", "GO") to
+// its canonical slug. It returns an *unknownSkillError if nothing matches.
+func canonicalSkill(ctx context.Context, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	skill, err := utils.Queries.ResolveSkill(ctx, generated.ResolveSkillParams{
+		Slug: strings.Trim(nonSlug.ReplaceAllString(name, "-"), "-"),
+		Name: name,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", &unknownSkillError{name: name}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return skill.Slug, nil
+}
+
+// canonicalSkills maps each of names to its canonical slug, dropping duplicates.
+func canonicalSkills(ctx context.Context, names []string) ([]string, error) {
+	slugs := make([]string, 0, len(names))
+	for _, name := range names {
+		slug, err := canonicalSkill(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(slugs, slug) {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs, nil
+}
+
+// sendSkillError reports an unknown skill as a bad request.
+func sendSkillError(w http.ResponseWriter, err error) {
+	var unknown *unknownSkillError
+	if errors.As(err, &unknown) {
+		utils.SendError(w, fmt.Sprintf("Unknown skill %q", unknown.name), http.StatusBadRequest)
+		return
+	}
+	utils.SendError(w, "Failed to resolve skills", http.StatusInternalServerError)
+}
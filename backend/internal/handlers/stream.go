@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egeuysall/summit/internal/events"
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// streamHeartbeat is how often Stream writes a comment to keep idle
+// connections from being closed by proxies.
+const streamHeartbeat = 25 * time.Second
+
+// Stream sends task events as Server-Sent Events. Anonymous clients receive
+// public events; authenticated clients also receive private events about
+// tasks they posted or claimed. Reconnecting clients that send Last-Event-ID
+// are replayed the events they missed, as far as the broker retains them.
+func Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.SendError(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var lastID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			utils.SendError(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastID = id
+	}
+
+	userID, _ := appmid.UserIDFromContext(r.Context())
+
+	stream, err := events.Subscribe(r.Context(), lastID)
+	if err != nil {
+		utils.SendError(w, "Failed to subscribe to events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case e, ok := <-stream:
+			if !ok {
+				return // Dropped by the broker; the client resumes from its last ID
+			}
+			if !e.VisibleTo(userID) {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+		}
+		flusher.Flush()
+	}
+}
@@ -1,27 +1,117 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	appmid "github.com/egeuysall/summit/internal/middleware"
 	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
 	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
 	"github.com/egeuysall/summit/internal/utils"
 )
 
-// ListTasks retrieves all open tasks.
+// ListTasks searches tasks. Accepts optional query parameters "q" (full-text
+// search over title and description), "skill", "urgency", "status" (default:
+// open), "min_reward", "max_reward", "requester" and "sort" (urgency, newest
+// or reward; default: urgency, most urgent first), plus "limit" and "cursor"
+// for paging.
 func ListTasks(w http.ResponseWriter, r *http.Request) {
-	tasks, err := utils.Queries.ListOpenTasks(r.Context())
+	query := r.URL.Query()
+
+	params := generated.SearchTasksParams{
+		Status:  string(tasks.Open),
+		Query:   optionalText(query.Get("q")),
+		Skill:   optionalText(query.Get("skill")),
+		Urgency: optionalText(query.Get("urgency")),
+		Sort:    "urgency",
+	}
+
+	if params.Urgency.Valid {
+		if _, ok := tasks.ParseUrgency(params.Urgency.String); !ok {
+			utils.SendError(w, "Urgency must be low, medium, high or critical", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if params.Skill.Valid {
+		skill, err := canonicalSkill(r.Context(), params.Skill.String)
+		if err != nil {
+			sendSkillError(w, err)
+			return
+		}
+		params.Skill.String = skill
+	}
+
+	if status := query.Get("status"); status != "" {
+		if !slices.Contains(searchableStatuses, tasks.Status(status)) {
+			utils.SendError(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+		params.Status = status
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		if !slices.Contains([]string{"urgency", "newest", "reward"}, sort) {
+			utils.SendError(w, "Sort must be urgency, newest or reward", http.StatusBadRequest)
+			return
+		}
+		params.Sort = sort
+	}
+
+	var err error
+	if params.MinReward, err = optionalInt(query.Get("min_reward")); err != nil {
+		utils.SendError(w, "Invalid min_reward", http.StatusBadRequest)
+		return
+	}
+	if params.MaxReward, err = optionalInt(query.Get("max_reward")); err != nil {
+		utils.SendError(w, "Invalid max_reward", http.StatusBadRequest)
+		return
+	}
+	if params.MinReward.Valid && params.MaxReward.Valid && params.MinReward.Int32 > params.MaxReward.Int32 {
+		utils.SendError(w, "min_reward cannot exceed max_reward", http.StatusBadRequest)
+		return
+	}
+
+	if requester := query.Get("requester"); requester != "" {
+		if params.RequesterID, err = utils.ParseUUID(requester); err != nil {
+			utils.SendError(w, "Invalid requester ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	params.AfterKey = page.AfterKey()
+	params.AfterCreatedAt = page.AfterCreatedAt()
+	params.AfterID = page.AfterID()
+	params.PageLimit = page.FetchLimit()
+
+	list, err := utils.Queries.SearchTasks(r.Context(), params)
 	if err != nil {
 		utils.SendError(w, "Failed to fetch tasks", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToTaskResponses(tasks), http.StatusOK)
+	list, next := utils.Paginate(list, page, func(t generated.Task) utils.Cursor {
+		cursor := taskCursor(t)
+		cursor.Key = searchRank(t, params.Sort)
+		return cursor
+	})
+	utils.SendPage(w, models.ToTaskResponses(list), next, http.StatusOK)
 }
 
 // CreateTask creates a new task for the authenticated user.
@@ -39,11 +129,13 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Title        string  `json:"title"`
-		Description  string  `json:"description"`
-		Skill        string  `json:"skill"`
-		Urgency      *string `json:"urgency,omitempty"`
-		CreditReward int32   `json:"credit_reward"`
+		Title          string     `json:"title"`
+		Description    string     `json:"description"`
+		Skill          string     `json:"skill"`
+		Urgency        *string    `json:"urgency,omitempty"`
+		UrgencyPremium bool       `json:"urgency_premium"`
+		CreditReward   int32      `json:"credit_reward"`
+		DueAt          *time.Time `json:"due_at,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -60,58 +152,72 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 		utils.SendError(w, "Credit reward must be positive", http.StatusBadRequest)
 		return
 	}
-
-	// Check if user has enough credits
-	profile, err := utils.Queries.GetProfile(r.Context(), uuid)
-	if err != nil {
-		utils.SendError(w, "Failed to fetch profile", http.StatusInternalServerError)
+	if req.CreditReward > tasks.MaxReward {
+		utils.SendError(w, fmt.Sprintf("Credit reward must be at most %d", tasks.MaxReward), http.StatusBadRequest)
 		return
 	}
 
-	if !profile.Credits.Valid || profile.Credits.Int32 < req.CreditReward {
-		utils.SendError(w, "Insufficient credits", http.StatusBadRequest)
-		return
+	urgency := tasks.UrgencyMedium
+	if req.Urgency != nil {
+		var ok bool
+		if urgency, ok = tasks.ParseUrgency(*req.Urgency); !ok {
+			utils.SendError(w, "Urgency must be low, medium, high or critical", http.StatusBadRequest)
+			return
+		}
 	}
 
-	var urgency pgtype.Text
-	if req.Urgency != nil {
-		urgency.String = *req.Urgency
-		urgency.Valid = true
+	var premium int32
+	if req.UrgencyPremium {
+		premium = urgency.Premium(req.CreditReward)
+		if premium == 0 {
+			utils.SendError(w, "Urgency premium requires high or critical urgency", http.StatusBadRequest)
+			return
+		}
 	}
 
-	params := generated.CreateTaskParams{
-		Title:        req.Title,
-		Description:  req.Description,
-		Skill:        req.Skill,
-		Urgency:      urgency,
-		CreditReward: req.CreditReward,
-		RequesterID:  uuid,
+	if req.DueAt != nil && !req.DueAt.After(time.Now()) {
+		utils.SendError(w, "Due date must be in the future", http.StatusBadRequest)
+		return
 	}
 
-	task, err := utils.Queries.CreateTask(r.Context(), params)
+	skill, err := canonicalSkill(r.Context(), req.Skill)
 	if err != nil {
-		utils.SendError(w, "Failed to create task", http.StatusInternalServerError)
+		sendSkillError(w, err)
 		return
 	}
 
-	// Deduct credits from requester
-	_, err = utils.Queries.DecrementCredits(r.Context(), generated.DecrementCreditsParams{
-		ID:      uuid,
-		Credits: pgtype.Int4{Int32: req.CreditReward, Valid: true},
-	})
+	// Check if user has enough credits
+	profile, err := utils.Queries.GetProfile(r.Context(), uuid)
 	if err != nil {
-		utils.SendError(w, "Failed to deduct credits", http.StatusInternalServerError)
+		utils.SendError(w, "Failed to fetch profile", http.StatusInternalServerError)
 		return
 	}
 
-	// Create transaction record for task posting
-	_, err = utils.Queries.CreateTransaction(r.Context(), generated.CreateTransactionParams{
-		UserID:  uuid,
-		TaskID:  pgtype.UUID{Bytes: task.ID.Bytes, Valid: true},
-		Credits: -req.CreditReward, // Negative because credits were spent
-	})
+	if !profile.Credits.Valid || profile.Credits.Int32 < req.CreditReward+premium {
+		utils.SendError(w, "Insufficient credits", http.StatusBadRequest)
+		return
+	}
+
+	var dueAt pgtype.Timestamptz
+	if req.DueAt != nil {
+		dueAt.Time = *req.DueAt
+		dueAt.Valid = true
+	}
+
+	params := generated.CreateTaskParams{
+		Title:          req.Title,
+		Description:    req.Description,
+		Skill:          skill,
+		Urgency:        string(urgency),
+		CreditReward:   req.CreditReward,
+		RequesterID:    uuid,
+		DueAt:          dueAt,
+		UrgencyPremium: premium,
+	}
+
+	task, err := services.CreateTask(r.Context(), params)
 	if err != nil {
-		utils.SendError(w, "Task created but failed to record transaction", http.StatusInternalServerError)
+		sendTaskError(w, err, "Failed to create task")
 		return
 	}
 
@@ -167,47 +273,15 @@ func DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify the task belongs to the requester
 	task, err := utils.Queries.GetTask(r.Context(), taskID)
 	if err != nil {
 		utils.SendError(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
-	if utils.UUIDToString(task.RequesterID) != userID {
-		utils.SendError(w, "You can only delete your own tasks", http.StatusForbidden)
-		return
-	}
-
-	if task.Status.Valid && task.Status.String != "open" {
-		utils.SendError(w, "Can only delete open tasks", http.StatusBadRequest)
-		return
-	}
-
-	err = utils.Queries.DeleteTask(r.Context(), taskID)
+	err = services.DeleteTask(r.Context(), task, uuid)
 	if err != nil {
-		utils.SendError(w, "Failed to delete task", http.StatusInternalServerError)
-		return
-	}
-
-	// Refund credits to requester
-	err = utils.Queries.IncrementCredits(r.Context(), generated.IncrementCreditsParams{
-		ID:      uuid,
-		Credits: pgtype.Int4{Int32: task.CreditReward, Valid: true},
-	})
-	if err != nil {
-		utils.SendError(w, "Task deleted but failed to refund credits", http.StatusInternalServerError)
-		return
-	}
-
-	// Create transaction record for refund
-	_, err = utils.Queries.CreateTransaction(r.Context(), generated.CreateTransactionParams{
-		UserID:  uuid,
-		TaskID:  pgtype.UUID{Bytes: taskID.Bytes, Valid: true},
-		Credits: task.CreditReward, // Positive because credits were refunded
-	})
-	if err != nil {
-		utils.SendError(w, "Task deleted and credits refunded but failed to record transaction", http.StatusInternalServerError)
+		sendTaskError(w, err, "Failed to delete task")
 		return
 	}
 
@@ -216,127 +290,106 @@ func DeleteTask(w http.ResponseWriter, r *http.Request) {
 
 // ClaimTask allows a user to claim an open task.
 func ClaimTask(w http.ResponseWriter, r *http.Request) {
-	userID, ok := appmid.UserIDFromContext(r.Context())
-	if !ok {
-		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
-		return
-	}
-
-	uuid, err := utils.ParseUUID(userID)
-	if err != nil {
-		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
+	updateTask(w, r, services.ClaimTask, "Failed to claim task")
+}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	if taskIDStr == "" {
-		utils.SendError(w, "Task ID is required", http.StatusBadRequest)
-		return
-	}
+// ReleaseTask returns a claimed task to the open pool on behalf of its claimer.
+func ReleaseTask(w http.ResponseWriter, r *http.Request) {
+	updateTask(w, r, services.ReleaseTask, "Failed to release task")
+}
 
-	taskID, err := utils.ParseUUID(taskIDStr)
-	if err != nil {
-		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
-		return
-	}
+// CompleteTask marks a task as completed by the claimer.
+func CompleteTask(w http.ResponseWriter, r *http.Request) {
+	updateTask(w, r, services.CompleteTask, "Failed to complete task")
+}
 
-	// Verify the task exists and is open
-	task, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task not found", http.StatusNotFound)
-		return
+// RejectTask sends completed work back to the claimer with a reason.
+func RejectTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
 	}
 
-	if task.Status.Valid && task.Status.String != "open" {
-		utils.SendError(w, "Task is not available for claiming", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if utils.UUIDToString(task.RequesterID) == userID {
-		utils.SendError(w, "You cannot claim your own task", http.StatusBadRequest)
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		utils.SendError(w, "Reason is required", http.StatusBadRequest)
 		return
 	}
 
-	err = utils.Queries.ClaimTask(r.Context(), generated.ClaimTaskParams{
-		ID:          taskID,
-		ClaimedByID: uuid,
-	})
-	if err != nil {
-		utils.SendError(w, "Failed to claim task", http.StatusInternalServerError)
-		return
-	}
+	updateTask(w, r, func(ctx context.Context, task generated.Task, actorID pgtype.UUID) (generated.Task, error) {
+		return services.RejectTask(ctx, task, actorID, reason)
+	}, "Failed to reject task")
+}
 
-	// Fetch and return the updated task
-	updatedTask, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task claimed but failed to fetch", http.StatusInternalServerError)
-		return
-	}
+// ConfirmTask confirms a completed task by the requester and transfers credits.
+func ConfirmTask(w http.ResponseWriter, r *http.Request) {
+	updateTask(w, r, services.ConfirmTask, "Failed to confirm task")
+}
 
-	utils.SendJson(w, models.ToTaskResponse(updatedTask), http.StatusOK)
+// CancelTask cancels a task and refunds credits to the requester.
+func CancelTask(w http.ResponseWriter, r *http.Request) {
+	updateTask(w, r, services.CancelTask, "Failed to cancel task")
 }
 
-// CompleteTask marks a task as completed by the claimer.
-func CompleteTask(w http.ResponseWriter, r *http.Request) {
+// recommendationCandidates is how many open tasks GetRecommendedTasks scores.
+const recommendationCandidates = 200
+
+// GetRecommendedTasks ranks open tasks posted by others for the authenticated
+// user by skill match, reward, urgency and recency.
+// Accepts optional query parameter "limit" (default: 20, max: 100).
+func GetRecommendedTasks(w http.ResponseWriter, r *http.Request) {
 	userID, ok := appmid.UserIDFromContext(r.Context())
 	if !ok {
 		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
 		return
 	}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	if taskIDStr == "" {
-		utils.SendError(w, "Task ID is required", http.StatusBadRequest)
-		return
-	}
-
-	taskID, err := utils.ParseUUID(taskIDStr)
+	uuid, err := utils.ParseUUID(userID)
 	if err != nil {
-		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
 		return
 	}
 
-	// Verify the task is claimed by this user
-	task, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task not found", http.StatusNotFound)
+	limit, ok := parseLimit(w, r, utils.DefaultPageLimit)
+	if !ok {
 		return
 	}
 
-	if !task.ClaimedByID.Valid || utils.UUIDToString(task.ClaimedByID) != userID {
-		utils.SendError(w, "You can only complete tasks you have claimed", http.StatusForbidden)
+	profile, err := utils.Queries.GetProfile(r.Context(), uuid)
+	if err != nil {
+		utils.SendError(w, "Profile not found", http.StatusNotFound)
 		return
 	}
 
-	if task.Status.Valid && task.Status.String != "claimed" {
-		utils.SendError(w, "Task must be claimed to mark as completed", http.StatusBadRequest)
-		return
+	skills := make([]string, len(profile.Skills))
+	for i, skill := range profile.Skills {
+		skills[i] = strings.ToLower(strings.TrimSpace(skill))
 	}
 
-	err = utils.Queries.CompleteTask(r.Context(), taskID)
+	candidates, err := utils.Queries.ListRecommendationCandidates(r.Context(), generated.ListRecommendationCandidatesParams{
+		UserID:         uuid,
+		Skills:         skills,
+		CandidateLimit: recommendationCandidates,
+	})
 	if err != nil {
-		utils.SendError(w, "Failed to complete task", http.StatusInternalServerError)
+		utils.SendError(w, "Failed to fetch recommended tasks", http.StatusInternalServerError)
 		return
 	}
 
-	// Fetch and return the updated task
-	updatedTask, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task completed but failed to fetch", http.StatusInternalServerError)
-		return
+	recs := tasks.Recommend(candidates, profile.Skills, time.Now())
+	if int32(len(recs)) > limit {
+		recs = recs[:limit]
 	}
 
-	utils.SendJson(w, models.ToTaskResponse(updatedTask), http.StatusOK)
+	utils.SendJson(w, models.ToRecommendationResponses(recs), http.StatusOK)
 }
 
-// ConfirmTask confirms a completed task by the requester and transfers credits.
-func ConfirmTask(w http.ResponseWriter, r *http.Request) {
-	userID, ok := appmid.UserIDFromContext(r.Context())
-	if !ok {
-		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
-		return
-	}
-
+// GetTaskHistory retrieves every status transition of a task.
+func GetTaskHistory(w http.ResponseWriter, r *http.Request) {
 	taskIDStr := chi.URLParam(r, "taskID")
 	if taskIDStr == "" {
 		utils.SendError(w, "Task ID is required", http.StatusBadRequest)
@@ -349,67 +402,56 @@ func ConfirmTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify the task belongs to the requester
-	task, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
+	if _, err := utils.Queries.GetTask(r.Context(), taskID); err != nil {
 		utils.SendError(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
-	if utils.UUIDToString(task.RequesterID) != userID {
-		utils.SendError(w, "Only the requester can confirm the task", http.StatusForbidden)
+	history, err := utils.Queries.GetTaskStatusHistory(r.Context(), taskID)
+	if err != nil {
+		utils.SendError(w, "Failed to fetch task history", http.StatusInternalServerError)
 		return
 	}
 
-	if task.Status.Valid && task.Status.String != "completed" {
-		utils.SendError(w, "Task must be completed to confirm", http.StatusBadRequest)
-		return
-	}
+	utils.SendJson(w, models.ToTaskStatusHistoryResponses(history), http.StatusOK)
+}
 
-	if !task.ClaimedByID.Valid {
-		utils.SendError(w, "Task has no claimer", http.StatusBadRequest)
+// GetMyPostedTasks retrieves tasks posted by the authenticated user.
+func GetMyPostedTasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
 		return
 	}
 
-	err = utils.Queries.ConfirmTask(r.Context(), taskID)
+	uuid, err := utils.ParseUUID(userID)
 	if err != nil {
-		utils.SendError(w, "Failed to confirm task", http.StatusInternalServerError)
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
 		return
 	}
 
-	// Transfer credits to the claimer
-	err = utils.Queries.IncrementCredits(r.Context(), generated.IncrementCreditsParams{
-		ID:      task.ClaimedByID,
-		Credits: pgtype.Int4{Int32: task.CreditReward, Valid: true},
-	})
-	if err != nil {
-		utils.SendError(w, "Task confirmed but failed to transfer credits", http.StatusInternalServerError)
+	page, ok := parsePage(w, r)
+	if !ok {
 		return
 	}
 
-	// Create transaction record for reward
-	_, err = utils.Queries.CreateTransaction(r.Context(), generated.CreateTransactionParams{
-		UserID:  task.ClaimedByID,
-		TaskID:  pgtype.UUID{Bytes: taskID.Bytes, Valid: true},
-		Credits: task.CreditReward, // Positive because credits were earned
+	list, err := utils.Queries.ListTasksByRequester(r.Context(), generated.ListTasksByRequesterParams{
+		RequesterID:    uuid,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
 	})
 	if err != nil {
-		utils.SendError(w, "Task confirmed and credits transferred but failed to record transaction", http.StatusInternalServerError)
-		return
-	}
-
-	// Fetch and return the updated task
-	updatedTask, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task confirmed but failed to fetch", http.StatusInternalServerError)
+		utils.SendError(w, "Failed to fetch posted tasks", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToTaskResponse(updatedTask), http.StatusOK)
+	list, next := utils.Paginate(list, page, taskCursor)
+	utils.SendPage(w, models.ToTaskResponses(list), next, http.StatusOK)
 }
 
-// CancelTask cancels a task and refunds credits to the requester.
-func CancelTask(w http.ResponseWriter, r *http.Request) {
+// GetMyClaimedTasks retrieves tasks claimed by the authenticated user.
+func GetMyClaimedTasks(w http.ResponseWriter, r *http.Request) {
 	userID, ok := appmid.UserIDFromContext(r.Context())
 	if !ok {
 		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
@@ -422,69 +464,68 @@ func CancelTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	if taskIDStr == "" {
-		utils.SendError(w, "Task ID is required", http.StatusBadRequest)
+	page, ok := parsePage(w, r)
+	if !ok {
 		return
 	}
 
-	taskID, err := utils.ParseUUID(taskIDStr)
+	list, err := utils.Queries.ListTasksByClaimer(r.Context(), generated.ListTasksByClaimerParams{
+		ClaimedByID:    uuid,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
 	if err != nil {
-		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
+		utils.SendError(w, "Failed to fetch claimed tasks", http.StatusInternalServerError)
 		return
 	}
 
-	// Verify the task belongs to the requester
-	task, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task not found", http.StatusNotFound)
-		return
-	}
+	list, next := utils.Paginate(list, page, taskCursor)
+	utils.SendPage(w, models.ToTaskResponses(list), next, http.StatusOK)
+}
 
-	if utils.UUIDToString(task.RequesterID) != userID {
-		utils.SendError(w, "Only the requester can cancel the task", http.StatusForbidden)
-		return
-	}
+// searchableStatuses are the statuses ListTasks can filter by.
+var searchableStatuses = []tasks.Status{
+	tasks.Open, tasks.Claimed, tasks.Completed, tasks.Confirmed,
+	tasks.Cancelled, tasks.Disputed, tasks.Expired,
+}
 
-	err = utils.Queries.CancelTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Failed to cancel task", http.StatusInternalServerError)
-		return
+// searchRank mirrors the sort key SearchTasks orders by, so the next page's
+// cursor resumes from the right place.
+func searchRank(t generated.Task, sort string) int32 {
+	switch sort {
+	case "reward":
+		return t.CreditReward
+	case "urgency":
+		return tasks.Urgency(t.Urgency).Rank()
 	}
+	return 0
+}
 
-	// Refund credits to requester
-	err = utils.Queries.IncrementCredits(r.Context(), generated.IncrementCreditsParams{
-		ID:      uuid,
-		Credits: pgtype.Int4{Int32: task.CreditReward, Valid: true},
-	})
-	if err != nil {
-		utils.SendError(w, "Task cancelled but failed to refund credits", http.StatusInternalServerError)
-		return
-	}
+// optionalText treats an empty query parameter as absent.
+func optionalText(value string) pgtype.Text {
+	value = strings.TrimSpace(value)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
 
-	// Create transaction record for refund
-	_, err = utils.Queries.CreateTransaction(r.Context(), generated.CreateTransactionParams{
-		UserID:  uuid,
-		TaskID:  pgtype.UUID{Bytes: taskID.Bytes, Valid: true},
-		Credits: task.CreditReward, // Positive because credits were refunded
-	})
-	if err != nil {
-		utils.SendError(w, "Task cancelled and credits refunded but failed to record transaction", http.StatusInternalServerError)
-		return
+// optionalInt parses a non-negative integer query parameter, treating an
+// empty value as absent.
+func optionalInt(value string) (pgtype.Int4, error) {
+	if value == "" {
+		return pgtype.Int4{}, nil
 	}
 
-	// Fetch and return the updated task
-	updatedTask, err := utils.Queries.GetTask(r.Context(), taskID)
-	if err != nil {
-		utils.SendError(w, "Task cancelled but failed to fetch", http.StatusInternalServerError)
-		return
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 0 {
+		return pgtype.Int4{}, fmt.Errorf("invalid integer %q", value)
 	}
 
-	utils.SendJson(w, models.ToTaskResponse(updatedTask), http.StatusOK)
+	return pgtype.Int4{Int32: int32(n), Valid: true}, nil
 }
 
-// GetMyPostedTasks retrieves tasks posted by the authenticated user.
-func GetMyPostedTasks(w http.ResponseWriter, r *http.Request) {
+// updateTask loads the task named in the URL and applies a lifecycle
+// transition to it on behalf of the authenticated user.
+func updateTask(w http.ResponseWriter, r *http.Request, apply func(context.Context, generated.Task, pgtype.UUID) (generated.Task, error), failure string) {
 	userID, ok := appmid.UserIDFromContext(r.Context())
 	if !ok {
 		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
@@ -497,34 +538,54 @@ func GetMyPostedTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := utils.Queries.ListTasksByRequester(r.Context(), uuid)
-	if err != nil {
-		utils.SendError(w, "Failed to fetch posted tasks", http.StatusInternalServerError)
+	taskIDStr := chi.URLParam(r, "taskID")
+	if taskIDStr == "" {
+		utils.SendError(w, "Task ID is required", http.StatusBadRequest)
 		return
 	}
 
-	utils.SendJson(w, models.ToTaskResponses(tasks), http.StatusOK)
-}
-
-// GetMyClaimedTasks retrieves tasks claimed by the authenticated user.
-func GetMyClaimedTasks(w http.ResponseWriter, r *http.Request) {
-	userID, ok := appmid.UserIDFromContext(r.Context())
-	if !ok {
-		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+	taskID, err := utils.ParseUUID(taskIDStr)
+	if err != nil {
+		utils.SendError(w, "Invalid task ID", http.StatusBadRequest)
 		return
 	}
 
-	uuid, err := utils.ParseUUID(userID)
+	task, err := utils.Queries.GetTask(r.Context(), taskID)
 	if err != nil {
-		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		utils.SendError(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
-	tasks, err := utils.Queries.ListTasksByClaimer(r.Context(), uuid)
+	updatedTask, err := apply(r.Context(), task, uuid)
 	if err != nil {
-		utils.SendError(w, "Failed to fetch claimed tasks", http.StatusInternalServerError)
+		sendTaskError(w, err, failure)
 		return
 	}
 
-	utils.SendJson(w, models.ToTaskResponses(tasks), http.StatusOK)
+	utils.SendJson(w, models.ToTaskResponse(updatedTask), http.StatusOK)
+}
+
+// sendTaskError maps errors from the task services to HTTP responses.
+func sendTaskError(w http.ResponseWriter, err error, failure string) {
+	var transitionErr *tasks.TransitionError
+	var trustErr *services.TrustError
+
+	switch {
+	case errors.As(err, &transitionErr) && errors.Is(err, tasks.ErrNotPermitted):
+		utils.SendError(w, fmt.Sprintf("You are not allowed to %s this task", transitionErr.Action), http.StatusForbidden)
+	case errors.As(err, &transitionErr):
+		utils.SendError(w, fmt.Sprintf("Cannot %s a task that is %s", transitionErr.Action, transitionErr.From), http.StatusBadRequest)
+	case errors.As(err, &trustErr):
+		utils.SendError(w, fmt.Sprintf("This task requires trust level %s", trustErr.Required), http.StatusForbidden)
+	case errors.Is(err, services.ErrTaskConflict):
+		utils.SendError(w, "Task was changed by another request", http.StatusConflict)
+	case errors.Is(err, services.ErrEscrowSettled):
+		utils.SendError(w, "Task credits have already been settled", http.StatusConflict)
+	case errors.Is(err, services.ErrDisputeResolved):
+		utils.SendError(w, "Dispute has already been resolved", http.StatusConflict)
+	case errors.Is(err, services.ErrInsufficientCredits):
+		utils.SendError(w, "Insufficient credits", http.StatusBadRequest)
+	default:
+		utils.SendError(w, failure, http.StatusInternalServerError)
+	}
 }
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	appmid "github.com/egeuysall/summit/internal/middleware"
 	"github.com/egeuysall/summit/internal/models"
@@ -11,7 +10,7 @@ import (
 )
 
 // GetMyTransactions retrieves transactions for the authenticated user.
-// Accepts optional query parameter "limit" (default: 50, max: 100)
+// Accepts optional query parameters "limit" (default: 20, max: 100) and "cursor".
 func GetMyTransactions(w http.ResponseWriter, r *http.Request) {
 	userID, ok := appmid.UserIDFromContext(r.Context())
 	if !ok {
@@ -25,24 +24,22 @@ func GetMyTransactions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse limit from query params (default 50, max 100)
-	limit := int32(50)
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.ParseInt(limitStr, 10, 32); err == nil {
-			if parsedLimit > 0 && parsedLimit <= 100 {
-				limit = int32(parsedLimit)
-			}
-		}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
 	}
 
 	transactions, err := utils.Queries.GetUserTransactions(r.Context(), generated.GetUserTransactionsParams{
-		UserID: uuid,
-		Limit:  limit,
+		UserID:         uuid,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
 	})
 	if err != nil {
 		utils.SendError(w, "Failed to fetch transactions", http.StatusInternalServerError)
 		return
 	}
 
-	utils.SendJson(w, models.ToTransactionResponses(transactions), http.StatusOK)
+	transactions, next := utils.Paginate(transactions, page, transactionCursor)
+	utils.SendPage(w, models.ToTransactionResponses(transactions), next, http.StatusOK)
 }
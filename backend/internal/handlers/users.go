@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// GetUser retrieves a user's public profile and activity stats. The credit
+// balance is only included when users request their own profile.
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "userID")
+	if userIDStr == "" {
+		utils.SendError(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := utils.ParseUUID(userIDStr)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := utils.Queries.GetProfile(r.Context(), userID)
+	if err != nil {
+		utils.SendError(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	stats, err := utils.Queries.GetUserStats(r.Context(), userID)
+	if err != nil {
+		utils.SendError(w, "Failed to fetch user stats", http.StatusInternalServerError)
+		return
+	}
+
+	rep, err := utils.Queries.GetUserReputation(r.Context(), userID)
+	if err != nil {
+		utils.SendError(w, "Failed to fetch user reputation", http.StatusInternalServerError)
+		return
+	}
+
+	callerID, _ := appmid.UserIDFromContext(r.Context())
+	self := callerID == utils.UUIDToString(userID)
+
+	utils.SendJson(w, models.ToPublicProfileResponse(profile, stats, rep, self), http.StatusOK)
+}
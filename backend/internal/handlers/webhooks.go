@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+
+	appmid "github.com/egeuysall/summit/internal/middleware"
+	"github.com/egeuysall/summit/internal/models"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/egeuysall/summit/internal/webhooks"
+)
+
+// deliveryStatuses are the values GetWebhookDeliveries can filter on.
+var deliveryStatuses = []string{"pending", "succeeded", "dead"}
+
+// CreateWebhook registers an endpoint for the authenticated user. Accepts
+// "url", "event_types" and, for admins only, "all_events" to receive every
+// event rather than just those about the user's own tasks and credits. The
+// signing secret is returned only in this response.
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		URL        string   `json:"url"`
+		EventTypes []string `json:"event_types"`
+		AllEvents  bool     `json:"all_events"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	endpoint, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		utils.SendError(w, "URL must be an absolute http or https URL", http.StatusBadRequest)
+		return
+	}
+	if err := webhooks.CheckURL(endpoint, webhooks.AllowPrivate()); err != nil {
+		utils.SendError(w, "URL must not point at localhost or a private network", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.EventTypes) == 0 {
+		utils.SendError(w, "At least one event type is required", http.StatusBadRequest)
+		return
+	}
+
+	eventTypes := make([]string, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(services.WebhookEventTypes, eventType) {
+			utils.SendError(w, "Unknown event type: "+eventType, http.StatusBadRequest)
+			return
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	if req.AllEvents && !appmid.IsAdmin(userID) {
+		utils.SendError(w, "Only admins can receive all events", http.StatusForbidden)
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		utils.SendError(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	webhook, err := utils.Queries.CreateWebhook(r.Context(), generated.CreateWebhookParams{
+		OwnerID:    uuid,
+		Url:        endpoint.String(),
+		Secret:     secret,
+		EventTypes: eventTypes,
+		AllEvents:  req.AllEvents,
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	response := models.ToWebhookResponse(webhook)
+	response.Secret = &webhook.Secret
+	utils.SendJson(w, response, http.StatusCreated)
+}
+
+// GetMyWebhooks lists the authenticated user's webhooks.
+func GetMyWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	uuid, err := utils.ParseUUID(userID)
+	if err != nil {
+		utils.SendError(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	webhooks, err := utils.Queries.ListWebhooksByOwner(r.Context(), uuid)
+	if err != nil {
+		utils.SendError(w, "Failed to fetch webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToWebhookResponses(webhooks), http.StatusOK)
+}
+
+// DeleteWebhook removes a webhook along with its deliveries.
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := utils.Queries.DeleteWebhook(r.Context(), webhook.ID); err != nil {
+		utils.SendError(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, map[string]string{"message": "Webhook deleted successfully"}, http.StatusOK)
+}
+
+// GetWebhookDeliveries lists a webhook's deliveries, newest first. Accepts
+// "status" (pending, succeeded or dead; dead lists the dead letters), plus
+// "limit" and "cursor" for paging.
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	status := optionalText(r.URL.Query().Get("status"))
+	if status.Valid && !slices.Contains(deliveryStatuses, status.String) {
+		utils.SendError(w, "Status must be pending, succeeded or dead", http.StatusBadRequest)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := utils.Queries.ListWebhookDeliveries(r.Context(), generated.ListWebhookDeliveriesParams{
+		WebhookID:      webhook.ID,
+		Status:         status,
+		AfterCreatedAt: page.AfterCreatedAt(),
+		AfterID:        page.AfterID(),
+		PageLimit:      page.FetchLimit(),
+	})
+	if err != nil {
+		utils.SendError(w, "Failed to fetch deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, next := utils.Paginate(deliveries, page, webhookDeliveryCursor)
+	utils.SendPage(w, models.ToWebhookDeliveryResponses(deliveries), next, http.StatusOK)
+}
+
+// ReplayWebhookDelivery queues a delivery to be sent again, as a new
+// delivery with the original payload.
+func ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := utils.ParseUUID(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		utils.SendError(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := utils.Queries.ReplayWebhookDelivery(r.Context(), generated.ReplayWebhookDeliveryParams{
+		ID:        deliveryID,
+		WebhookID: webhook.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, "Delivery not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.SendError(w, "Failed to replay delivery", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJson(w, models.ToWebhookDeliveryResponse(delivery), http.StatusCreated)
+}
+
+// loadWebhook loads the webhook named in the URL if it belongs to the
+// authenticated user or the user is an admin. It writes an error response
+// and returns false otherwise.
+func loadWebhook(w http.ResponseWriter, r *http.Request) (generated.Webhook, bool) {
+	userID, ok := appmid.UserIDFromContext(r.Context())
+	if !ok {
+		utils.SendError(w, "User ID not found in context", http.StatusUnauthorized)
+		return generated.Webhook{}, false
+	}
+
+	webhookID, err := utils.ParseUUID(chi.URLParam(r, "webhookID"))
+	if err != nil {
+		utils.SendError(w, "Invalid webhook ID", http.StatusBadRequest)
+		return generated.Webhook{}, false
+	}
+
+	webhook, err := utils.Queries.GetWebhook(r.Context(), webhookID)
+	if err != nil || (utils.UUIDToString(webhook.OwnerID) != userID && !appmid.IsAdmin(userID)) {
+		utils.SendError(w, "Webhook not found", http.StatusNotFound)
+		return generated.Webhook{}, false
+	}
+
+	return webhook, true
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/egeuysall/summit/internal/services"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AutoConfirm returns a job that confirms tasks which have sat in completed
+// for longer than grace, paying the claimer as if the requester had confirmed.
+func AutoConfirm(grace time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		tasks, err := utils.Queries.ListTasksCompletedBefore(ctx, pgtype.Timestamptz{
+			Time:  time.Now().Add(-grace),
+			Valid: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			_, err := services.AutoConfirmTask(ctx, task)
+			if errors.Is(err, services.ErrTaskConflict) {
+				continue // Confirmed, rejected or cancelled since we listed it
+			}
+			if err != nil {
+				log.Printf("Failed to auto-confirm task %s: %v", utils.UUIDToString(task.ID), err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/egeuysall/summit/internal/mailer"
+	"github.com/egeuysall/summit/internal/services"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// emailBatch is how many notifications or digest recipients one run
+// handles. digestTasks caps how many tasks one digest lists.
+const (
+	emailBatch  = 50
+	digestTasks = 10
+)
+
+// digestInterval is how long after a user's last digest the next one is
+// due. It is a little under a day so that the hourly job doesn't push each
+// user's digest an hour later every day.
+const digestInterval = 23 * time.Hour
+
+// EmailLinks builds the links in emails: task pages on the frontend at
+// AppURL and the unsubscribe endpoint on the API at APIURL.
+type EmailLinks struct {
+	AppURL string
+	APIURL string
+}
+
+func (l EmailLinks) task(id pgtype.UUID) string {
+	return strings.TrimSuffix(l.AppURL, "/") + "/dashboard/tasks/" + utils.UUIDToString(id)
+}
+
+func (l EmailLinks) tasks() string {
+	return strings.TrimSuffix(l.AppURL, "/") + "/dashboard/tasks"
+}
+
+func (l EmailLinks) unsubscribe(token pgtype.UUID, kind string) string {
+	query := url.Values{"token": {utils.UUIDToString(token)}, "type": {kind}}
+	return strings.TrimSuffix(l.APIURL, "/") + "/v1/email/unsubscribe?" + query.Encode()
+}
+
+// SendNotificationEmails returns a job that emails users about their task
+// being claimed and work being ready to confirm. Each notification is
+// emailed at most once; a failed send is logged and not retried.
+func SendNotificationEmails(m mailer.Mailer, links EmailLinks) func(context.Context) error {
+	return func(ctx context.Context) error {
+		due, err := utils.Queries.ClaimNotificationEmails(ctx, generated.ClaimNotificationEmailsParams{
+			BatchSize: emailBatch,
+			Types:     services.EmailedNotifications,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, n := range due {
+			var template, subject string
+			switch n.Type {
+			case services.NotifyTaskClaimed:
+				template = mailer.TemplateTaskClaimed
+				subject = fmt.Sprintf("Your task %q was claimed", n.Title)
+			case services.NotifyTaskCompleted:
+				template = mailer.TemplateTaskCompleted
+				subject = fmt.Sprintf("Work on %q is ready to confirm", n.Title)
+			default:
+				continue
+			}
+
+			unsubscribe := links.unsubscribe(n.UnsubscribeToken, "email_"+n.Type)
+			err := send(ctx, m, n.Email, subject, template, unsubscribe, mailer.TaskEmail{
+				Name:           n.Name,
+				Title:          n.Title,
+				Reward:         n.CreditReward,
+				ClaimerName:    n.ClaimerName.String,
+				TaskURL:        links.task(n.TaskID),
+				UnsubscribeURL: unsubscribe,
+			})
+			if err != nil {
+				log.Printf("Failed to email notification %s: %v", utils.UUIDToString(n.ID), err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// SendDigests returns a job that emails each user with skills on their
+// profile a digest of open tasks in those skills posted since their last
+// one. Users with nothing new are skipped until the next day.
+func SendDigests(m mailer.Mailer, links EmailLinks) func(context.Context) error {
+	return func(ctx context.Context) error {
+		recipients, err := utils.Queries.ClaimDigestRecipients(ctx, generated.ClaimDigestRecipientsParams{
+			DueBefore: pgtype.Timestamptz{Time: time.Now().Add(-digestInterval), Valid: true},
+			BatchSize: emailBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, recipient := range recipients {
+			tasks, err := utils.Queries.ListDigestTasks(ctx, generated.ListDigestTasksParams{
+				Skills:    recipient.Skills,
+				Since:     recipient.Since,
+				UserID:    recipient.UserID,
+				PageLimit: digestTasks,
+			})
+			if err != nil {
+				log.Printf("Failed to list digest tasks for user %s: %v", utils.UUIDToString(recipient.UserID), err)
+				continue
+			}
+			if len(tasks) == 0 {
+				continue
+			}
+
+			digest := mailer.DigestEmail{
+				Name:           recipient.Name,
+				TasksURL:       links.tasks(),
+				UnsubscribeURL: links.unsubscribe(recipient.UnsubscribeToken, services.EmailDigest),
+			}
+			for _, task := range tasks {
+				digest.Tasks = append(digest.Tasks, mailer.DigestTask{
+					Title:  task.Title,
+					Skill:  task.Skill,
+					Reward: task.CreditReward,
+					URL:    links.task(task.ID),
+				})
+			}
+
+			subject := fmt.Sprintf("%d new tasks matching your skills", len(tasks))
+			if len(tasks) == 1 {
+				subject = "A new task matching your skills"
+			}
+
+			err = send(ctx, m, recipient.Email, subject, mailer.TemplateDigest, digest.UnsubscribeURL, digest)
+			if err != nil {
+				log.Printf("Failed to email digest to user %s: %v", utils.UUIDToString(recipient.UserID), err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// send renders a template and mails it with one-click unsubscribe headers.
+func send(ctx context.Context, m mailer.Mailer, to, subject, template, unsubscribe string, data any) error {
+	text, html, err := mailer.Render(template, data)
+	if err != nil {
+		return err
+	}
+
+	return m.Send(ctx, mailer.Message{
+		To:      to,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/egeuysall/summit/internal/services"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ExpireOverdue returns a job that handles tasks past their deadline: open
+// tasks expire with a refund and claimed tasks go back to the open pool.
+func ExpireOverdue() func(context.Context) error {
+	return func(ctx context.Context) error {
+		overdue, err := utils.Queries.ListTasksDueBefore(ctx, pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range overdue {
+			if tasks.StatusOf(task) == tasks.Claimed {
+				_, err = services.ExpireClaim(ctx, task)
+			} else {
+				_, err = services.ExpireTask(ctx, task)
+			}
+			if errors.Is(err, services.ErrTaskConflict) {
+				continue // Moved on since we listed it
+			}
+			if err != nil {
+				log.Printf("Failed to expire task %s: %v", utils.UUIDToString(task.ID), err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/egeuysall/summit/internal/webhooks"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// webhookBatch is how many deliveries one run sends. With the client's
+// timeout it bounds how long a run takes, which must stay under
+// webhookLease so that no other worker picks the same deliveries up.
+const (
+	webhookBatch = 20
+	webhookLease = 10 * time.Minute
+)
+
+// DeliverWebhooks returns a job that sends due webhook deliveries. Failed
+// deliveries are retried with exponential backoff and marked dead after
+// webhooks.MaxAttempts attempts.
+func DeliverWebhooks(client *http.Client) func(context.Context) error {
+	return func(ctx context.Context) error {
+		due, err := utils.Queries.ClaimDueWebhookDeliveries(ctx, generated.ClaimDueWebhookDeliveriesParams{
+			LeaseUntil: pgtype.Timestamptz{Time: time.Now().Add(webhookLease), Valid: true},
+			BatchSize:  webhookBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, d := range due {
+			status, err := webhooks.Send(ctx, client, webhooks.Delivery{
+				ID:        utils.UUIDToString(d.ID),
+				EventType: d.EventType,
+				URL:       d.Url,
+				Secret:    d.Secret,
+				Payload:   d.Payload,
+			})
+
+			responseStatus := pgtype.Int4{Int32: int32(status), Valid: status != 0}
+			if err == nil {
+				err = utils.Queries.MarkWebhookDeliverySucceeded(ctx, generated.MarkWebhookDeliverySucceededParams{
+					ID:             d.ID,
+					ResponseStatus: responseStatus,
+				})
+			} else {
+				err = markWebhookFailed(ctx, d, responseStatus, err)
+			}
+			if err != nil {
+				log.Printf("Failed to record webhook delivery %s: %v", utils.UUIDToString(d.ID), err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// markWebhookFailed schedules the delivery's next attempt, or marks it dead
+// if it has none left.
+func markWebhookFailed(ctx context.Context, d generated.ClaimDueWebhookDeliveriesRow, responseStatus pgtype.Int4, sendErr error) error {
+	attempts := d.Attempts + 1
+
+	status := "pending"
+	if attempts >= webhooks.MaxAttempts {
+		status = "dead"
+	}
+
+	return utils.Queries.MarkWebhookDeliveryFailed(ctx, generated.MarkWebhookDeliveryFailedParams{
+		Status:         status,
+		ResponseStatus: responseStatus,
+		LastError:      pgtype.Text{String: sendErr.Error(), Valid: true},
+		NextAttemptAt:  pgtype.Timestamptz{Time: time.Now().Add(webhooks.Backoff(attempts)), Valid: true},
+		ID:             d.ID,
+	})
+}
@@ -0,0 +1,174 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/egeuysall/summit/internal/webhooks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		respond    int
+		attempts   int32
+		wantStatus string
+	}{
+		{"success", http.StatusOK, 0, "succeeded"},
+		{"first failure is retried", http.StatusInternalServerError, 0, "pending"},
+		{"later failure is retried", http.StatusBadGateway, webhooks.MaxAttempts - 2, "pending"},
+		{"last failure is dead", http.StatusInternalServerError, webhooks.MaxAttempts - 1, "dead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.respond)
+			}))
+			defer receiver.Close()
+
+			db := &fakeDeliveryDB{due: []generated.ClaimDueWebhookDeliveriesRow{{
+				ID:        pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+				EventType: "task.claimed",
+				Payload:   []byte(`{}`),
+				Attempts:  tt.attempts,
+				Url:       receiver.URL,
+				Secret:    "whsec_test",
+			}}}
+			utils.Init(generated.New(db))
+
+			start := time.Now()
+			if err := DeliverWebhooks(webhooks.NewClient(time.Second, true))(context.Background()); err != nil {
+				t.Fatalf("DeliverWebhooks: %v", err)
+			}
+
+			if tt.wantStatus == "succeeded" {
+				if len(db.succeeded) != 1 || len(db.failed) != 0 {
+					t.Fatalf("recorded %d successes and %d failures, want 1 success", len(db.succeeded), len(db.failed))
+				}
+				if got := db.succeeded[0].ResponseStatus; got.Int32 != int32(tt.respond) {
+					t.Errorf("response status = %d, want %d", got.Int32, tt.respond)
+				}
+				return
+			}
+
+			if len(db.failed) != 1 || len(db.succeeded) != 0 {
+				t.Fatalf("recorded %d successes and %d failures, want 1 failure", len(db.succeeded), len(db.failed))
+			}
+			failed := db.failed[0]
+			if failed.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", failed.Status, tt.wantStatus)
+			}
+			if failed.ResponseStatus.Int32 != int32(tt.respond) {
+				t.Errorf("response status = %d, want %d", failed.ResponseStatus.Int32, tt.respond)
+			}
+			if !failed.LastError.Valid {
+				t.Error("last error not recorded")
+			}
+
+			wait := webhooks.Backoff(tt.attempts + 1)
+			if next := failed.NextAttemptAt.Time; next.Before(start.Add(wait)) || next.After(time.Now().Add(wait)) {
+				t.Errorf("next attempt in %v, want %v", next.Sub(start), wait)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhooksRetriesUnreachableEndpoints(t *testing.T) {
+	db := &fakeDeliveryDB{due: []generated.ClaimDueWebhookDeliveriesRow{{
+		ID:      pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+		Payload: []byte(`{}`),
+		Url:     "http://127.0.0.1:1/hooks",
+	}}}
+	utils.Init(generated.New(db))
+
+	if err := DeliverWebhooks(webhooks.NewClient(time.Second, false))(context.Background()); err != nil {
+		t.Fatalf("DeliverWebhooks: %v", err)
+	}
+
+	if len(db.failed) != 1 {
+		t.Fatalf("recorded %d failures, want 1", len(db.failed))
+	}
+	if failed := db.failed[0]; failed.Status != "pending" || failed.ResponseStatus.Valid {
+		t.Errorf("recorded status %q with response %v, want pending with no response", failed.Status, failed.ResponseStatus)
+	}
+}
+
+// fakeDeliveryDB serves ClaimDueWebhookDeliveries from due and records the
+// outcomes DeliverWebhooks writes back.
+type fakeDeliveryDB struct {
+	due       []generated.ClaimDueWebhookDeliveriesRow
+	succeeded []generated.MarkWebhookDeliverySucceededParams
+	failed    []generated.MarkWebhookDeliveryFailedParams
+}
+
+func (db *fakeDeliveryDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	switch {
+	case strings.HasPrefix(sql, "-- name: MarkWebhookDeliverySucceeded "):
+		db.succeeded = append(db.succeeded, generated.MarkWebhookDeliverySucceededParams{
+			ID:             args[0].(pgtype.UUID),
+			ResponseStatus: args[1].(pgtype.Int4),
+		})
+	case strings.HasPrefix(sql, "-- name: MarkWebhookDeliveryFailed "):
+		db.failed = append(db.failed, generated.MarkWebhookDeliveryFailedParams{
+			Status:         args[0].(string),
+			ResponseStatus: args[1].(pgtype.Int4),
+			LastError:      args[2].(pgtype.Text),
+			NextAttemptAt:  args[3].(pgtype.Timestamptz),
+			ID:             args[4].(pgtype.UUID),
+		})
+	default:
+		return pgconn.CommandTag{}, fmt.Errorf("unexpected exec: %.60s", sql)
+	}
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (db *fakeDeliveryDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if !strings.HasPrefix(sql, "-- name: ClaimDueWebhookDeliveries ") {
+		return nil, fmt.Errorf("unexpected query: %.60s", sql)
+	}
+	due := db.due
+	db.due = nil
+	return &deliveryRows{rows: due}, nil
+}
+
+func (db *fakeDeliveryDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	panic("unexpected query: " + sql)
+}
+
+// deliveryRows iterates over claimed deliveries. The pgx.Rows methods
+// generated code does not call are left to the nil embedded interface.
+type deliveryRows struct {
+	pgx.Rows
+	rows []generated.ClaimDueWebhookDeliveriesRow
+	next int
+}
+
+func (r *deliveryRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *deliveryRows) Scan(dest ...any) error {
+	row := r.rows[r.next-1]
+	*dest[0].(*pgtype.UUID) = row.ID
+	*dest[1].(*string) = row.EventType
+	*dest[2].(*[]byte) = row.Payload
+	*dest[3].(*int32) = row.Attempts
+	*dest[4].(*string) = row.Url
+	*dest[5].(*string) = row.Secret
+	return nil
+}
+
+func (r *deliveryRows) Close()     {}
+func (r *deliveryRows) Err() error { return nil }
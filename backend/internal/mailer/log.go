@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Log writes each message, headers and all, to a writer instead of sending
+// it. It is meant for local runs, writing to stdout or a file.
+type Log struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewLog returns a Mailer that writes messages to w.
+func NewLog(w io.Writer, from string) *Log {
+	return &Log{w: w, from: from}
+}
+
+// Send writes msg followed by a separator line.
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	data, err := msg.Bytes(l.from)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err = fmt.Fprintf(l.w, "%s\r\n-----\r\n", data)
+	return err
+}
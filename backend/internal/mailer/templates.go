@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Templates. Each has a .txt and a .html file that fill in the layout's
+// "content" block.
+const (
+	TemplateTaskClaimed   = "task_claimed"
+	TemplateTaskCompleted = "task_completed"
+	TemplateDigest        = "digest"
+)
+
+// TaskEmail is the data for TemplateTaskClaimed and TemplateTaskCompleted.
+type TaskEmail struct {
+	Name           string
+	Title          string
+	Reward         int32
+	ClaimerName    string
+	TaskURL        string
+	UnsubscribeURL string
+}
+
+// DigestEmail is the data for TemplateDigest.
+type DigestEmail struct {
+	Name           string
+	Tasks          []DigestTask
+	TasksURL       string
+	UnsubscribeURL string
+}
+
+// DigestTask is one task listed in a digest.
+type DigestTask struct {
+	Title  string
+	Skill  string
+	Reward int32
+	URL    string
+}
+
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	textTemplates = map[string]*texttemplate.Template{}
+	htmlTemplates = map[string]*htmltemplate.Template{}
+)
+
+func init() {
+	for _, name := range []string{TemplateTaskClaimed, TemplateTaskCompleted, TemplateDigest} {
+		textTemplates[name] = texttemplate.Must(texttemplate.ParseFS(templateFiles,
+			"templates/layout.txt", "templates/"+name+".txt"))
+		htmlTemplates[name] = htmltemplate.Must(htmltemplate.ParseFS(templateFiles,
+			"templates/layout.html", "templates/"+name+".html"))
+	}
+}
+
+// Render fills in the named template's plain text and HTML versions.
+func Render(name string, data any) (text, html string, err error) {
+	textTemplate, ok := textTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", name)
+	}
+
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&textBuf, "layout", data); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplates[name].ExecuteTemplate(&htmlBuf, "layout", data); err != nil {
+		return "", "", err
+	}
+
+	return textBuf.String(), htmlBuf.String(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -17,95 +18,180 @@ type contextKey string
 
 const userIDKey = contextKey("userID")
 
-func RequireAuth() func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			supabaseJWTSecret := strings.TrimSpace(os.Getenv("SUPABASE_JWT_SECRET"))
-			supabaseIssuer := os.Getenv("SUPABASE_ISSUER")
+// authError is why a request failed authentication and the status to answer with.
+type authError struct {
+	message string
+	status  int
+}
 
-			supabaseAudience := "authenticated"
-			customAud := os.Getenv("SUPABASE_AUDIENCE")
+// authenticate validates the request's Supabase JWT and returns its subject.
+func authenticate(r *http.Request) (string, *authError) {
+	supabaseJWTSecret := strings.TrimSpace(os.Getenv("SUPABASE_JWT_SECRET"))
+	supabaseIssuer := os.Getenv("SUPABASE_ISSUER")
 
-			if customAud != "" {
-				supabaseAudience = customAud
-			}
+	supabaseAudience := "authenticated"
+	customAud := os.Getenv("SUPABASE_AUDIENCE")
 
-			if supabaseJWTSecret == "" {
-				utils.SendError(w, "Internal server error", http.StatusInternalServerError)
-				return
-			}
+	if customAud != "" {
+		supabaseAudience = customAud
+	}
 
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				utils.SendError(w, "Unauthorized: missing Authorization header", http.StatusUnauthorized)
-				return
-			}
+	if supabaseJWTSecret == "" {
+		return "", &authError{"Internal server error", http.StatusInternalServerError}
+	}
 
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-				utils.SendError(w, "Unauthorized: invalid Authorization header format", http.StatusUnauthorized)
-				return
-			}
-			tokenStr := parts[1]
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return "", &authError{"Unauthorized: missing Authorization header", http.StatusUnauthorized}
+	}
 
-			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte(supabaseJWTSecret), nil
-			})
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", &authError{"Unauthorized: invalid Authorization header format", http.StatusUnauthorized}
+	}
+	tokenStr := parts[1]
 
-			if err != nil {
-				utils.SendError(w, "Unauthorized: invalid token", http.StatusUnauthorized)
-				return
-			}
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(supabaseJWTSecret), nil
+	})
 
-			if !token.Valid {
-				utils.SendError(w, "Unauthorized: invalid token", http.StatusUnauthorized)
-				return
-			}
+	if err != nil {
+		return "", &authError{"Unauthorized: invalid token", http.StatusUnauthorized}
+	}
+
+	if !token.Valid {
+		return "", &authError{"Unauthorized: invalid token", http.StatusUnauthorized}
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", &authError{"Unauthorized: invalid token claims", http.StatusUnauthorized}
+	}
+
+	if iss, ok := claims["iss"].(string); !ok || (supabaseIssuer != "" && iss != supabaseIssuer) {
+		return "", &authError{"Unauthorized: invalid issuer", http.StatusUnauthorized}
+	}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				utils.SendError(w, "Unauthorized: invalid token claims", http.StatusUnauthorized)
+	if aud, ok := claims["aud"].(string); !ok || (supabaseAudience != "" && aud != supabaseAudience) {
+		return "", &authError{"Unauthorized: invalid audience", http.StatusUnauthorized}
+	}
+
+	if exp, ok := claims["exp"].(float64); !ok || int64(exp) < time.Now().Unix() {
+		return "", &authError{"Unauthorized: token expired", http.StatusUnauthorized}
+	}
+
+	sub, ok := claims["sub"].(string)
+	if !ok || sub == "" {
+		return "", &authError{"Unauthorized: missing subject", http.StatusUnauthorized}
+	}
+
+	return sub, nil
+}
+
+func RequireAuth() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sub, authErr := authenticate(r)
+			if authErr != nil {
+				utils.SendError(w, authErr.message, authErr.status)
 				return
 			}
 
-			if iss, ok := claims["iss"].(string); !ok || (supabaseIssuer != "" && iss != supabaseIssuer) {
-				utils.SendError(w, "Unauthorized: invalid issuer", http.StatusUnauthorized)
+			ctx := context.WithValue(r.Context(), userIDKey, sub)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuth identifies the caller when an Authorization header is present
+// and lets anonymous requests through. A header with a bad token is still
+// rejected, so clients learn their session expired.
+func OptionalAuth() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			if aud, ok := claims["aud"].(string); !ok || (supabaseAudience != "" && aud != supabaseAudience) {
-				utils.SendError(w, "Unauthorized: invalid audience", http.StatusUnauthorized)
+			sub, authErr := authenticate(r)
+			if authErr != nil {
+				utils.SendError(w, authErr.message, authErr.status)
 				return
 			}
 
-			if exp, ok := claims["exp"].(float64); !ok || int64(exp) < time.Now().Unix() {
-				utils.SendError(w, "Unauthorized: token expired", http.StatusUnauthorized)
-				return
+			ctx := context.WithValue(r.Context(), userIDKey, sub)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TokenFromQuery lets clients that cannot set headers, such as browser
+// WebSockets, pass their token as the "access_token" query parameter. It
+// must run before RequireAuth or OptionalAuth.
+func TokenFromQuery() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+				r.Header.Set("Authorization", "Bearer "+token)
 			}
 
-			sub, ok := claims["sub"].(string)
-			if !ok || sub == "" {
-				utils.SendError(w, "Unauthorized: missing subject", http.StatusUnauthorized)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAdmin allows only users listed in ADMIN_USER_IDS. It must run after RequireAuth.
+func RequireAdmin() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok || !IsAdmin(userID) {
+				utils.SendError(w, "Forbidden: admin access required", http.StatusForbidden)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), userIDKey, sub)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// IsAdmin reports whether userID is listed in the comma-separated ADMIN_USER_IDS.
+func IsAdmin(userID string) bool {
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" && id == userID {
+			return true
+		}
+	}
+	return false
+}
+
 func UserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(userIDKey).(string)
 	return userID, ok
 }
 
+// AllowedOrigins are the browser origins allowed to call the API.
+var AllowedOrigins = []string{"http://localhost:3000", "https://www.summit.egeuysal.com"}
+
+// AllowedOriginHosts returns the hosts of AllowedOrigins, as WebSocket
+// origin patterns.
+func AllowedOriginHosts() []string {
+	hosts := make([]string, 0, len(AllowedOrigins))
+	for _, origin := range AllowedOrigins {
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Host)
+		}
+	}
+	return hosts
+}
+
 func Cors() func(next http.Handler) http.Handler {
 	return cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000", "https://www.summit.egeuysal.com"},
+		AllowedOrigins:   AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
 		AllowCredentials: true,
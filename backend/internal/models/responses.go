@@ -1,46 +1,195 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"time"
 
 	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
 	"github.com/egeuysall/summit/internal/utils"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // ProfileResponse represents a profile with snake_case JSON tags
 type ProfileResponse struct {
-	ID        string   `json:"id"`
-	Name      string   `json:"name"`
-	AvatarURL *string  `json:"avatar_url,omitempty"`
-	Skills    []string `json:"skills"`
-	Credits   int32    `json:"credits"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	AvatarURL  *string  `json:"avatar_url,omitempty"`
+	Skills     []string `json:"skills"`
+	Credits    int32    `json:"credits"`
+	Reputation int32    `json:"reputation"`
+	TrustLevel string   `json:"trust_level"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// PublicProfileResponse represents another user's profile with snake_case JSON tags.
+// Credits is only set when users view their own profile.
+type PublicProfileResponse struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	AvatarURL  *string           `json:"avatar_url,omitempty"`
+	Skills     []string          `json:"skills"`
+	Credits    *int32            `json:"credits,omitempty"`
+	Reputation int32             `json:"reputation"`
+	TrustLevel string            `json:"trust_level"`
+	Stats      UserStatsResponse `json:"stats"`
+	CreatedAt  string            `json:"created_at"`
+}
+
+// UserStatsResponse represents a user's activity with snake_case JSON tags
+type UserStatsResponse struct {
+	TasksPosted          int32    `json:"tasks_posted"`
+	TasksCompleted       int32    `json:"tasks_completed"`
+	ConfirmationRate     *float64 `json:"confirmation_rate"`
+	AvgSecondsToComplete *int64   `json:"avg_seconds_to_complete"`
+	CreditsEarned        int32    `json:"credits_earned"`
+	SkillsUsed           []string `json:"skills_used"`
+	ReviewCount          int32    `json:"review_count"`
+	AverageRating        *float64 `json:"average_rating"`
+}
+
+// ReviewResponse represents a review of a task party with snake_case JSON tags
+type ReviewResponse struct {
+	ID           string  `json:"id"`
+	TaskID       string  `json:"task_id"`
+	ReviewerID   string  `json:"reviewer_id"`
+	ReviewerName *string `json:"reviewer_name,omitempty"`
+	RevieweeID   string  `json:"reviewee_id"`
+	Rating       int32   `json:"rating"`
+	Review       string  `json:"review"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// MessageResponse represents a task conversation message with snake_case JSON tags
+type MessageResponse struct {
+	ID        string  `json:"id"`
+	TaskID    string  `json:"task_id"`
+	SenderID  string  `json:"sender_id"`
+	Body      string  `json:"body"`
+	CreatedAt string  `json:"created_at"`
+	ReadAt    *string `json:"read_at"`
+}
+
+// ReadReceiptResponse reports messages that a task party has read
+type ReadReceiptResponse struct {
+	TaskID     string   `json:"task_id"`
+	ReaderID   string   `json:"reader_id"`
+	MessageIDs []string `json:"message_ids"`
+	ReadAt     string   `json:"read_at"`
+}
+
+// NotificationResponse represents an inbox notification with snake_case JSON tags
+type NotificationResponse struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	TaskID    *string `json:"task_id,omitempty"`
+	Message   string  `json:"message"`
+	Read      bool    `json:"read"`
+	CreatedAt string  `json:"created_at"`
+	ReadAt    *string `json:"read_at"`
+}
+
+// WebhookResponse represents a webhook endpoint with snake_case JSON tags.
+// Secret is only set when the webhook is created.
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	AllEvents  bool     `json:"all_events"`
+	Secret     *string  `json:"secret,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// WebhookDeliveryResponse represents a webhook delivery with snake_case JSON tags
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus *int32          `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	NextAttemptAt  *string         `json:"next_attempt_at"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    *string         `json:"delivered_at"`
 }
 
 // LeaderboardEntryResponse represents a leaderboard entry with snake_case JSON tags
 type LeaderboardEntryResponse struct {
-	ID        string  `json:"id"`
-	Name      string  `json:"name"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	Credits   int32   `json:"credits"`
-	Rank      int     `json:"rank"`
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	AvatarURL  *string `json:"avatar_url,omitempty"`
+	Reputation int32   `json:"reputation"`
+	TrustLevel string  `json:"trust_level"`
+	Score      int32   `json:"score"`
+	Rank       int32   `json:"rank"`
+}
+
+// SkillResponse represents a canonical skill with snake_case JSON tags
+type SkillResponse struct {
+	Slug     string   `json:"slug"`
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Aliases  []string `json:"aliases"`
 }
 
 // TaskResponse represents a task with snake_case JSON tags
 type TaskResponse struct {
-	ID           string  `json:"id"`
-	Title        string  `json:"title"`
-	Description  string  `json:"description"`
-	Skill        string  `json:"skill"`
-	Urgency      *string `json:"urgency,omitempty"`
-	CreditReward int32   `json:"credit_reward"`
-	RequesterID  string  `json:"requester_id"`
-	ClaimedByID  *string `json:"claimed_by_id,omitempty"`
-	Status       string  `json:"status"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
+	ID              string  `json:"id"`
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	Skill           string  `json:"skill"`
+	Urgency         string  `json:"urgency"`
+	UrgencyPremium  int32   `json:"urgency_premium"`
+	CreditReward    int32   `json:"credit_reward"`
+	RequiredTrust   string  `json:"required_trust_level"`
+	RequesterID     string  `json:"requester_id"`
+	ClaimedByID     *string `json:"claimed_by_id,omitempty"`
+	Status          string  `json:"status"`
+	EscrowAmount    int32   `json:"escrow_amount"`
+	EscrowState     string  `json:"escrow_state"`
+	RejectionReason *string `json:"rejection_reason,omitempty"`
+	RejectionCount  int32   `json:"rejection_count"`
+	DueAt           *string `json:"due_at,omitempty"`
+	Expired         bool    `json:"expired"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+// RecommendationResponse represents a recommended task and why it was recommended
+type RecommendationResponse struct {
+	Task          TaskResponse `json:"task"`
+	Score         float64      `json:"score"`
+	MatchedSkills []string     `json:"matched_skills"`
+}
+
+// TaskStatusHistoryResponse represents a task status transition with snake_case JSON tags
+type TaskStatusHistoryResponse struct {
+	ID         string  `json:"id"`
+	TaskID     string  `json:"task_id"`
+	Action     string  `json:"action"`
+	FromStatus *string `json:"from_status,omitempty"`
+	ToStatus   string  `json:"to_status"`
+	ActorID    *string `json:"actor_id,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// DisputeResponse represents a task dispute with snake_case JSON tags
+type DisputeResponse struct {
+	ID             string  `json:"id"`
+	TaskID         string  `json:"task_id"`
+	OpenedByID     string  `json:"opened_by_id"`
+	Reason         string  `json:"reason"`
+	Status         string  `json:"status"`
+	Outcome        *string `json:"outcome,omitempty"`
+	ClaimerPercent *int32  `json:"claimer_percent,omitempty"`
+	ResolutionNote *string `json:"resolution_note,omitempty"`
+	ResolvedByID   *string `json:"resolved_by_id,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	ResolvedAt     *string `json:"resolved_at,omitempty"`
 }
 
 // TransactionResponse represents a transaction with snake_case JSON tags
@@ -62,53 +211,259 @@ type RewardResponse struct {
 	Description *string `json:"description,omitempty"`
 }
 
-// ToProfileResponse converts a generated Profile to ProfileResponse
-func ToProfileResponse(p generated.Profile) ProfileResponse {
+// RedemptionResponse represents a redeemed reward with snake_case JSON tags
+type RedemptionResponse struct {
+	ID         string `json:"id"`
+	RewardID   int32  `json:"reward_id"`
+	RewardName string `json:"reward_name"`
+	Planet     string `json:"planet"`
+	Cost       int32  `json:"cost"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ToProfileResponse converts a generated Profile and its reputation to ProfileResponse
+func ToProfileResponse(p generated.Profile, rep generated.UserReputation) ProfileResponse {
 	var avatarURL *string
 	if p.AvatarUrl.Valid {
 		avatarURL = &p.AvatarUrl.String
 	}
 
 	return ProfileResponse{
-		ID:        utils.UUIDToString(p.ID),
-		Name:      p.Name,
-		AvatarURL: avatarURL,
-		Skills:    p.Skills,
-		Credits:   p.Credits.Int32,
+		ID:         utils.UUIDToString(p.ID),
+		Name:       p.Name,
+		AvatarURL:  avatarURL,
+		Skills:     p.Skills,
+		Credits:    p.Credits.Int32,
+		Reputation: rep.Score,
+		TrustLevel: tasks.TrustLevelFor(rep.Score).String(),
+		CreatedAt:  formatTimestamp(p.CreatedAt),
+		UpdatedAt:  formatTimestamp(p.CreatedAt), // Use created_at as updated_at since we don't track updates yet
+	}
+}
+
+// ToPublicProfileResponse converts a generated Profile, its stats and reputation
+// to PublicProfileResponse, including the credit balance only if self is true
+func ToPublicProfileResponse(p generated.Profile, stats generated.GetUserStatsRow, rep generated.UserReputation, self bool) PublicProfileResponse {
+	var avatarURL *string
+	if p.AvatarUrl.Valid {
+		avatarURL = &p.AvatarUrl.String
+	}
+
+	var credits *int32
+	if self {
+		credits = &p.Credits.Int32
+	}
+
+	// Rate and average are undefined until the user has submitted work
+	var confirmationRate *float64
+	var avgSeconds *int64
+	if stats.TasksSubmitted > 0 {
+		rate := math.Round(float64(stats.TasksCompleted)/float64(stats.TasksSubmitted)*1000) / 1000
+		confirmationRate = &rate
+		avg := int64(math.Round(stats.AvgSecondsToComplete))
+		avgSeconds = &avg
+	}
+
+	var averageRating *float64
+	if stats.ReviewCount > 0 {
+		avg := math.Round(stats.AverageRating*100) / 100
+		averageRating = &avg
+	}
+
+	return PublicProfileResponse{
+		ID:         utils.UUIDToString(p.ID),
+		Name:       p.Name,
+		AvatarURL:  avatarURL,
+		Skills:     p.Skills,
+		Credits:    credits,
+		Reputation: rep.Score,
+		TrustLevel: tasks.TrustLevelFor(rep.Score).String(),
+		Stats: UserStatsResponse{
+			TasksPosted:          stats.TasksPosted,
+			TasksCompleted:       stats.TasksCompleted,
+			ConfirmationRate:     confirmationRate,
+			AvgSecondsToComplete: avgSeconds,
+			CreditsEarned:        stats.CreditsEarned,
+			SkillsUsed:           stats.SkillsUsed,
+			ReviewCount:          stats.ReviewCount,
+			AverageRating:        averageRating,
+		},
 		CreatedAt: formatTimestamp(p.CreatedAt),
-		UpdatedAt: formatTimestamp(p.CreatedAt), // Use created_at as updated_at since we don't track updates yet
 	}
 }
 
-// ToLeaderboardEntryResponse converts a GetLeaderboardRow to LeaderboardEntryResponse with rank
-func ToLeaderboardEntryResponse(row generated.GetLeaderboardRow, rank int) LeaderboardEntryResponse {
+// ToReviewResponse converts a generated Review to ReviewResponse
+func ToReviewResponse(r generated.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:         utils.UUIDToString(r.ID),
+		TaskID:     utils.UUIDToString(r.TaskID),
+		ReviewerID: utils.UUIDToString(r.ReviewerID),
+		RevieweeID: utils.UUIDToString(r.RevieweeID),
+		Rating:     r.Rating,
+		Review:     r.Body,
+		CreatedAt:  formatTimestamp(r.CreatedAt),
+	}
+}
+
+// ToUserReviewResponse converts a ListUserReviewsRow to ReviewResponse
+func ToUserReviewResponse(row generated.ListUserReviewsRow) ReviewResponse {
+	return ReviewResponse{
+		ID:           utils.UUIDToString(row.ID),
+		TaskID:       utils.UUIDToString(row.TaskID),
+		ReviewerID:   utils.UUIDToString(row.ReviewerID),
+		ReviewerName: &row.ReviewerName,
+		RevieweeID:   utils.UUIDToString(row.RevieweeID),
+		Rating:       row.Rating,
+		Review:       row.Body,
+		CreatedAt:    formatTimestamp(row.CreatedAt),
+	}
+}
+
+// ToMessageResponse converts a generated TaskMessage to MessageResponse
+func ToMessageResponse(m generated.TaskMessage) MessageResponse {
+	var readAt *string
+	if m.ReadAt.Valid {
+		ts := formatTimestamp(m.ReadAt)
+		readAt = &ts
+	}
+
+	return MessageResponse{
+		ID:        utils.UUIDToString(m.ID),
+		TaskID:    utils.UUIDToString(m.TaskID),
+		SenderID:  utils.UUIDToString(m.SenderID),
+		Body:      m.Body,
+		CreatedAt: formatTimestamp(m.CreatedAt),
+		ReadAt:    readAt,
+	}
+}
+
+// ToReadReceiptResponse summarizes messages newly marked as read by readerID
+func ToReadReceiptResponse(taskID, readerID pgtype.UUID, messages []generated.TaskMessage) ReadReceiptResponse {
+	receipt := ReadReceiptResponse{
+		TaskID:     utils.UUIDToString(taskID),
+		ReaderID:   utils.UUIDToString(readerID),
+		MessageIDs: make([]string, len(messages)),
+	}
+	for i, m := range messages {
+		receipt.MessageIDs[i] = utils.UUIDToString(m.ID)
+		receipt.ReadAt = formatTimestamp(m.ReadAt)
+	}
+	return receipt
+}
+
+// ToNotificationResponse converts a generated Notification to NotificationResponse
+func ToNotificationResponse(n generated.Notification) NotificationResponse {
+	var taskID *string
+	if n.TaskID.Valid {
+		id := utils.UUIDToString(n.TaskID)
+		taskID = &id
+	}
+
+	var readAt *string
+	if n.ReadAt.Valid {
+		ts := formatTimestamp(n.ReadAt)
+		readAt = &ts
+	}
+
+	return NotificationResponse{
+		ID:        utils.UUIDToString(n.ID),
+		Type:      n.Type,
+		TaskID:    taskID,
+		Message:   n.Message,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: formatTimestamp(n.CreatedAt),
+		ReadAt:    readAt,
+	}
+}
+
+// ToWebhookResponse converts a generated Webhook to WebhookResponse
+func ToWebhookResponse(w generated.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         utils.UUIDToString(w.ID),
+		URL:        w.Url,
+		EventTypes: w.EventTypes,
+		AllEvents:  w.AllEvents,
+		CreatedAt:  formatTimestamp(w.CreatedAt),
+	}
+}
+
+// ToWebhookDeliveryResponse converts a generated WebhookDelivery to WebhookDeliveryResponse
+func ToWebhookDeliveryResponse(d generated.WebhookDelivery) WebhookDeliveryResponse {
+	var responseStatus *int32
+	if d.ResponseStatus.Valid {
+		responseStatus = &d.ResponseStatus.Int32
+	}
+
+	var lastError *string
+	if d.LastError.Valid {
+		lastError = &d.LastError.String
+	}
+
+	// Only pending deliveries have a next attempt
+	var nextAttemptAt *string
+	if d.Status == "pending" {
+		ts := formatTimestamp(d.NextAttemptAt)
+		nextAttemptAt = &ts
+	}
+
+	var deliveredAt *string
+	if d.DeliveredAt.Valid {
+		ts := formatTimestamp(d.DeliveredAt)
+		deliveredAt = &ts
+	}
+
+	return WebhookDeliveryResponse{
+		ID:             utils.UUIDToString(d.ID),
+		WebhookID:      utils.UUIDToString(d.WebhookID),
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: responseStatus,
+		LastError:      lastError,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      formatTimestamp(d.CreatedAt),
+		DeliveredAt:    deliveredAt,
+	}
+}
+
+// ToLeaderboardEntryResponse converts a GetLeaderboardRow to LeaderboardEntryResponse
+func ToLeaderboardEntryResponse(row generated.GetLeaderboardRow) LeaderboardEntryResponse {
 	var avatarURL *string
 	if row.AvatarUrl.Valid {
 		avatarURL = &row.AvatarUrl.String
 	}
 
 	return LeaderboardEntryResponse{
-		ID:        utils.UUIDToString(row.ID),
-		Name:      row.Name,
-		AvatarURL: avatarURL,
-		Credits:   row.Credits.Int32,
-		Rank:      rank,
+		ID:         utils.UUIDToString(row.ID),
+		Name:       row.Name,
+		AvatarURL:  avatarURL,
+		Reputation: row.Reputation,
+		TrustLevel: tasks.TrustLevelFor(row.Reputation).String(),
+		Score:      row.Score,
+		Rank:       row.Rank,
 	}
 }
 
 // ToTaskResponse converts a generated Task to TaskResponse
 func ToTaskResponse(t generated.Task) TaskResponse {
-	var urgency *string
-	if t.Urgency.Valid {
-		urgency = &t.Urgency.String
-	}
-
 	var claimedByID *string
 	if t.ClaimedByID.Valid {
 		id := utils.UUIDToString(t.ClaimedByID)
 		claimedByID = &id
 	}
 
+	var rejectionReason *string
+	if t.RejectionReason.Valid {
+		rejectionReason = &t.RejectionReason.String
+	}
+
+	var dueAt *string
+	if t.DueAt.Valid {
+		ts := formatTimestamp(t.DueAt)
+		dueAt = &ts
+	}
+
 	// Status should always have a value, default to "open" if not set
 	status := "open"
 	if t.Status.Valid && t.Status.String != "" {
@@ -116,52 +471,140 @@ func ToTaskResponse(t generated.Task) TaskResponse {
 	}
 
 	return TaskResponse{
-		ID:           utils.UUIDToString(t.ID),
-		Title:        t.Title,
-		Description:  t.Description,
-		Skill:        t.Skill,
-		Urgency:      urgency,
-		CreditReward: t.CreditReward,
-		RequesterID:  utils.UUIDToString(t.RequesterID),
-		ClaimedByID:  claimedByID,
-		Status:       status,
-		CreatedAt:    formatTimestamp(t.CreatedAt),
-		UpdatedAt:    formatTimestamp(t.CreatedAt), // Use created_at as updated_at since we don't track updates yet
+		ID:              utils.UUIDToString(t.ID),
+		Title:           t.Title,
+		Description:     t.Description,
+		Skill:           t.Skill,
+		Urgency:         t.Urgency,
+		UrgencyPremium:  t.UrgencyPremium,
+		CreditReward:    t.CreditReward,
+		RequiredTrust:   tasks.RequiredTrustLevel(t.CreditReward).String(),
+		RequesterID:     utils.UUIDToString(t.RequesterID),
+		ClaimedByID:     claimedByID,
+		Status:          status,
+		EscrowAmount:    t.EscrowAmount,
+		EscrowState:     t.EscrowState,
+		RejectionReason: rejectionReason,
+		RejectionCount:  t.RejectionCount,
+		DueAt:           dueAt,
+		Expired:         status == "expired",
+		CreatedAt:       formatTimestamp(t.CreatedAt),
+		UpdatedAt:       formatTimestamp(t.CreatedAt), // Use created_at as updated_at since we don't track updates yet
 	}
 }
 
-// ToTransactionResponse converts a generated Transaction to TransactionResponse
-func ToTransactionResponse(t generated.Transaction) TransactionResponse {
-	// Determine transaction type based on amount
-	transactionType := "task_reward"
-	if t.Credits < 0 {
-		transactionType = "task_posted"
+// ToRecommendationResponse converts a tasks.Recommendation to RecommendationResponse
+func ToRecommendationResponse(rec tasks.Recommendation) RecommendationResponse {
+	return RecommendationResponse{
+		Task:          ToTaskResponse(rec.Task),
+		Score:         rec.Score,
+		MatchedSkills: rec.MatchedSkills,
+	}
+}
+
+// ToTaskStatusHistoryResponse converts a generated TaskStatusHistory to TaskStatusHistoryResponse
+func ToTaskStatusHistoryResponse(h generated.TaskStatusHistory) TaskStatusHistoryResponse {
+	var fromStatus *string
+	if h.FromStatus.Valid {
+		fromStatus = &h.FromStatus.String
+	}
+
+	var actorID *string
+	if h.ActorID.Valid {
+		id := utils.UUIDToString(h.ActorID)
+		actorID = &id
+	}
+
+	return TaskStatusHistoryResponse{
+		ID:         utils.UUIDToString(h.ID),
+		TaskID:     utils.UUIDToString(h.TaskID),
+		Action:     h.Action,
+		FromStatus: fromStatus,
+		ToStatus:   h.ToStatus,
+		ActorID:    actorID,
+		CreatedAt:  formatTimestamp(h.CreatedAt),
+	}
+}
+
+// ToDisputeResponse converts a generated Dispute to DisputeResponse
+func ToDisputeResponse(d generated.Dispute) DisputeResponse {
+	var outcome *string
+	if d.Outcome.Valid {
+		outcome = &d.Outcome.String
 	}
 
-	// Generate description
+	var claimerPercent *int32
+	if d.ClaimerPercent.Valid {
+		claimerPercent = &d.ClaimerPercent.Int32
+	}
+
+	var resolutionNote *string
+	if d.ResolutionNote.Valid {
+		resolutionNote = &d.ResolutionNote.String
+	}
+
+	var resolvedByID *string
+	if d.ResolvedByID.Valid {
+		id := utils.UUIDToString(d.ResolvedByID)
+		resolvedByID = &id
+	}
+
+	var resolvedAt *string
+	if d.ResolvedAt.Valid {
+		ts := formatTimestamp(d.ResolvedAt)
+		resolvedAt = &ts
+	}
+
+	return DisputeResponse{
+		ID:             utils.UUIDToString(d.ID),
+		TaskID:         utils.UUIDToString(d.TaskID),
+		OpenedByID:     utils.UUIDToString(d.OpenedByID),
+		Reason:         d.Reason,
+		Status:         d.Status,
+		Outcome:        outcome,
+		ClaimerPercent: claimerPercent,
+		ResolutionNote: resolutionNote,
+		ResolvedByID:   resolvedByID,
+		CreatedAt:      formatTimestamp(d.CreatedAt),
+		ResolvedAt:     resolvedAt,
+	}
+}
+
+// ToTransactionResponse converts a generated Transaction to TransactionResponse
+func ToTransactionResponse(t generated.Transaction) TransactionResponse {
 	var description *string
-	if t.TaskID.Valid {
-		taskIDStr := utils.UUIDToString(t.TaskID)
-		var desc string
-		if t.Credits > 0 {
-			desc = "Credits earned from completing task"
-		} else {
-			desc = "Credits spent on posting task"
-		}
+	if desc := transactionDescription(t.Kind); desc != "" {
 		description = &desc
-		_ = taskIDStr // Task ID available if needed for future enhancements
 	}
 
 	return TransactionResponse{
 		ID:              utils.UUIDToString(t.ID),
 		UserID:          utils.UUIDToString(t.UserID),
 		Amount:          t.Credits,
-		TransactionType: transactionType,
+		TransactionType: t.Kind,
 		Description:     description,
 		CreatedAt:       formatTimestamp(t.CreatedAt),
 	}
 }
 
+func transactionDescription(kind string) string {
+	switch kind {
+	case "task_escrow":
+		return "Credits spent on posting task"
+	case "task_refund":
+		return "Credits refunded for task"
+	case "task_payout":
+		return "Credits earned from completing task"
+	case "signup_bonus":
+		return "Welcome bonus for joining Summit"
+	case "redemption":
+		return "Credits spent on reward"
+	case "admin_adjustment":
+		return "Balance adjusted by an administrator"
+	}
+	return ""
+}
+
 // ToRewardResponse converts a generated Reward to RewardResponse
 func ToRewardResponse(r generated.Reward) RewardResponse {
 	var description *string
@@ -178,6 +621,30 @@ func ToRewardResponse(r generated.Reward) RewardResponse {
 	}
 }
 
+// ToRedemptionResponse converts a generated Redemption and its Reward to RedemptionResponse
+func ToRedemptionResponse(r generated.Redemption, reward generated.Reward) RedemptionResponse {
+	return RedemptionResponse{
+		ID:         utils.UUIDToString(r.ID),
+		RewardID:   r.RewardID,
+		RewardName: reward.Name,
+		Planet:     reward.Planet,
+		Cost:       r.Cost,
+		CreatedAt:  formatTimestamp(r.CreatedAt),
+	}
+}
+
+// ToUserRedemptionResponse converts a GetUserRedemptionsRow to RedemptionResponse
+func ToUserRedemptionResponse(row generated.GetUserRedemptionsRow) RedemptionResponse {
+	return RedemptionResponse{
+		ID:         utils.UUIDToString(row.ID),
+		RewardID:   row.RewardID,
+		RewardName: row.RewardName,
+		Planet:     row.Planet,
+		Cost:       row.Cost,
+		CreatedAt:  formatTimestamp(row.CreatedAt),
+	}
+}
+
 // Helper function to format timestamps
 func formatTimestamp(ts pgtype.Timestamptz) string {
 	if !ts.Valid {
@@ -187,10 +654,42 @@ func formatTimestamp(ts pgtype.Timestamptz) string {
 }
 
 // Batch conversion helpers
-func ToProfileResponses(profiles []generated.Profile) []ProfileResponse {
-	responses := make([]ProfileResponse, len(profiles))
-	for i, p := range profiles {
-		responses[i] = ToProfileResponse(p)
+func ToWebhookResponses(webhooks []generated.Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		responses[i] = ToWebhookResponse(w)
+	}
+	return responses
+}
+
+func ToWebhookDeliveryResponses(deliveries []generated.WebhookDelivery) []WebhookDeliveryResponse {
+	responses := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = ToWebhookDeliveryResponse(d)
+	}
+	return responses
+}
+
+func ToNotificationResponses(notifications []generated.Notification) []NotificationResponse {
+	responses := make([]NotificationResponse, len(notifications))
+	for i, n := range notifications {
+		responses[i] = ToNotificationResponse(n)
+	}
+	return responses
+}
+
+func ToMessageResponses(messages []generated.TaskMessage) []MessageResponse {
+	responses := make([]MessageResponse, len(messages))
+	for i, m := range messages {
+		responses[i] = ToMessageResponse(m)
+	}
+	return responses
+}
+
+func ToUserReviewResponses(rows []generated.ListUserReviewsRow) []ReviewResponse {
+	responses := make([]ReviewResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = ToUserReviewResponse(row)
 	}
 	return responses
 }
@@ -198,7 +697,20 @@ func ToProfileResponses(profiles []generated.Profile) []ProfileResponse {
 func ToLeaderboardResponses(rows []generated.GetLeaderboardRow) []LeaderboardEntryResponse {
 	responses := make([]LeaderboardEntryResponse, len(rows))
 	for i, row := range rows {
-		responses[i] = ToLeaderboardEntryResponse(row, i+1)
+		responses[i] = ToLeaderboardEntryResponse(row)
+	}
+	return responses
+}
+
+func ToSkillResponses(rows []generated.ListSkillsRow) []SkillResponse {
+	responses := make([]SkillResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = SkillResponse{
+			Slug:     row.Slug,
+			Name:     row.Name,
+			Category: row.Category,
+			Aliases:  row.Aliases,
+		}
 	}
 	return responses
 }
@@ -211,6 +723,30 @@ func ToTaskResponses(tasks []generated.Task) []TaskResponse {
 	return responses
 }
 
+func ToRecommendationResponses(recs []tasks.Recommendation) []RecommendationResponse {
+	responses := make([]RecommendationResponse, len(recs))
+	for i, rec := range recs {
+		responses[i] = ToRecommendationResponse(rec)
+	}
+	return responses
+}
+
+func ToTaskStatusHistoryResponses(history []generated.TaskStatusHistory) []TaskStatusHistoryResponse {
+	responses := make([]TaskStatusHistoryResponse, len(history))
+	for i, h := range history {
+		responses[i] = ToTaskStatusHistoryResponse(h)
+	}
+	return responses
+}
+
+func ToDisputeResponses(disputes []generated.Dispute) []DisputeResponse {
+	responses := make([]DisputeResponse, len(disputes))
+	for i, d := range disputes {
+		responses[i] = ToDisputeResponse(d)
+	}
+	return responses
+}
+
 func ToTransactionResponses(transactions []generated.Transaction) []TransactionResponse {
 	responses := make([]TransactionResponse, len(transactions))
 	for i, t := range transactions {
@@ -226,3 +762,11 @@ func ToRewardResponses(rewards []generated.Reward) []RewardResponse {
 	}
 	return responses
 }
+
+func ToUserRedemptionResponses(rows []generated.GetUserRedemptionsRow) []RedemptionResponse {
+	responses := make([]RedemptionResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = ToUserRedemptionResponse(row)
+	}
+	return responses
+}
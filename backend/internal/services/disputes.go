@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Dispute outcomes an admin can choose when resolving a dispute.
+const (
+	OutcomeAward  = "award"
+	OutcomeRefund = "refund"
+	OutcomeSplit  = "split"
+)
+
+var ErrDisputeResolved = errors.New("dispute already resolved")
+
+// OpenDispute freezes a claimed or completed task and its escrow until an
+// admin resolves the dispute.
+func OpenDispute(ctx context.Context, task generated.Task, actorID pgtype.UUID, reason string) (generated.Dispute, error) {
+	var dispute generated.Dispute
+
+	_, err := transition(ctx, task, tasks.Dispute, actorID, func(q *generated.Queries) (generated.Task, error) {
+		disputed, err := q.DisputeTask(ctx, generated.DisputeTaskParams{
+			ID:     task.ID,
+			Status: task.Status,
+		})
+		if err != nil {
+			return disputed, err
+		}
+
+		dispute, err = q.CreateDispute(ctx, generated.CreateDisputeParams{
+			TaskID:     task.ID,
+			OpenedByID: actorID,
+			Reason:     reason,
+		})
+		return disputed, err
+	})
+
+	return dispute, err
+}
+
+// ResolveDispute settles a disputed task on behalf of an admin. The claimer
+// receives claimerPercent of the escrow and the requester the rest; a full
+// refund cancels the task, any payout confirms it.
+func ResolveDispute(ctx context.Context, dispute generated.Dispute, task generated.Task, adminID pgtype.UUID, outcome string, claimerPercent int32, note string) (generated.Dispute, error) {
+	status := tasks.Confirmed
+	if claimerPercent == 0 {
+		status = tasks.Cancelled
+	}
+
+	var resolved generated.Dispute
+
+	_, err := transitionAs(ctx, task, tasks.Resolve, tasks.Admin, adminID, func(q *generated.Queries) (generated.Task, error) {
+		var err error
+		resolved, err = q.ResolveDispute(ctx, generated.ResolveDisputeParams{
+			ID:             dispute.ID,
+			Outcome:        pgtype.Text{String: outcome, Valid: true},
+			ClaimerPercent: pgtype.Int4{Int32: claimerPercent, Valid: true},
+			ResolutionNote: pgtype.Text{String: note, Valid: note != ""},
+			ResolvedByID:   adminID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return generated.Task{}, ErrDisputeResolved
+		}
+		if err != nil {
+			return generated.Task{}, err
+		}
+
+		settled, err := q.ResolveTask(ctx, generated.ResolveTaskParams{
+			ID:     task.ID,
+			Status: pgtype.Text{String: string(status), Valid: true},
+		})
+		if err != nil {
+			return settled, err
+		}
+
+		return settled, distributeEscrow(ctx, q, settled, claimerPercent)
+	})
+
+	return resolved, err
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Escrow states for the credits a task holds between posting and payout.
+const (
+	EscrowHeld     = "held"
+	EscrowReleased = "released"
+	EscrowRefunded = "refunded"
+	EscrowSplit    = "split"
+)
+
+// releaseEscrow pays everything the task holds in escrow to its claimer.
+func releaseEscrow(ctx context.Context, q *generated.Queries, task generated.Task) error {
+	return distributeEscrow(ctx, q, task, 100)
+}
+
+// refundEscrow returns everything the task holds in escrow to its requester.
+func refundEscrow(ctx context.Context, q *generated.Queries, task generated.Task) error {
+	return distributeEscrow(ctx, q, task, 0)
+}
+
+// refundDeletedEscrow returns a deleted task's escrow to its requester. The
+// task row is gone, so the refund is not linked to it, just as the task's
+// earlier ledger rows lose their link when it is deleted.
+func refundDeletedEscrow(ctx context.Context, q *generated.Queries, task generated.Task) error {
+	if task.EscrowState != EscrowHeld {
+		return ErrEscrowSettled
+	}
+	if task.EscrowAmount == 0 {
+		return nil
+	}
+
+	_, err := credit(ctx, q, task.RequesterID, pgtype.UUID{}, task.EscrowAmount, KindTaskRefund)
+	return err
+}
+
+// distributeEscrow settles the task's escrow, paying claimerPercent of it to
+// the claimer and refunding the rest to the requester.
+func distributeEscrow(ctx context.Context, q *generated.Queries, task generated.Task, claimerPercent int32) error {
+	state := EscrowSplit
+	switch claimerPercent {
+	case 100:
+		state = EscrowReleased
+	case 0:
+		state = EscrowRefunded
+	}
+
+	amount, err := q.SettleEscrow(ctx, generated.SettleEscrowParams{
+		ID:          task.ID,
+		EscrowState: state,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrEscrowSettled
+	}
+	if err != nil {
+		return err
+	}
+
+	payout, refund := splitEscrow(amount, claimerPercent)
+	if payout > 0 {
+		if _, err := credit(ctx, q, task.ClaimedByID, task.ID, payout, KindTaskPayout); err != nil {
+			return err
+		}
+	}
+
+	if refund > 0 {
+		if _, err := credit(ctx, q, task.RequesterID, task.ID, refund, KindTaskRefund); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitEscrow divides amount into the claimer's claimerPercent share and the
+// requester's remainder. The product is taken in int64, since amount times a
+// percentage can exceed int32 even when both fit.
+func splitEscrow(amount, claimerPercent int32) (payout, refund int32) {
+	payout = int32(int64(amount) * int64(claimerPercent) / 100)
+	return payout, amount - payout
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitEscrow(t *testing.T) {
+	tests := []struct {
+		amount, claimerPercent int32
+		payout, refund         int32
+	}{
+		{100, 100, 100, 0},
+		{100, 0, 0, 100},
+		{100, 50, 50, 50},
+		{7, 50, 3, 4},
+		{math.MaxInt32, 100, math.MaxInt32, 0},
+		{math.MaxInt32, 50, math.MaxInt32 / 2, math.MaxInt32 - math.MaxInt32/2},
+		{math.MaxInt32, 99, 2126008810, 21474837},
+	}
+
+	for _, tt := range tests {
+		payout, refund := splitEscrow(tt.amount, tt.claimerPercent)
+		if payout != tt.payout || refund != tt.refund {
+			t.Errorf("splitEscrow(%d, %d) = %d, %d; want %d, %d",
+				tt.amount, tt.claimerPercent, payout, refund, tt.payout, tt.refund)
+		}
+		if payout+refund != tt.amount {
+			t.Errorf("splitEscrow(%d, %d) split %d credits, want %d",
+				tt.amount, tt.claimerPercent, payout+refund, tt.amount)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/egeuysall/summit/internal/events"
+	"github.com/egeuysall/summit/internal/models"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/egeuysall/summit/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// taskEvents maps task actions to the event they publish to live
+// subscribers and webhooks.
+var taskEvents = map[tasks.Action]string{
+	tasks.Create:      events.TaskCreated,
+	tasks.Claim:       events.TaskClaimed,
+	tasks.Release:     events.TaskReleased,
+	tasks.ExpireClaim: events.TaskReleased,
+	tasks.Complete:    events.TaskCompleted,
+	tasks.Reject:      events.TaskRejected,
+	tasks.Confirm:     events.TaskConfirmed,
+	tasks.AutoConfirm: events.TaskConfirmed,
+	tasks.Cancel:      events.TaskCancelled,
+	tasks.Expire:      events.TaskExpired,
+	tasks.Dispute:     events.TaskDisputed,
+	tasks.Resolve:     events.TaskResolved,
+}
+
+// privateTaskEvents only reach the task's requester and claimer. The others
+// are public, since they change what the open task feed shows.
+var privateTaskEvents = map[string]bool{
+	events.TaskCompleted: true,
+	events.TaskRejected:  true,
+	events.TaskConfirmed: true,
+	events.TaskDisputed:  true,
+	events.TaskResolved:  true,
+}
+
+// taskParties returns the requester and anyone who held the claim before or
+// after a change. before is the task as it was read, since some transitions
+// clear the claimer.
+func taskParties(before, after generated.Task) []pgtype.UUID {
+	parties := []pgtype.UUID{after.RequesterID}
+	for _, claimer := range []pgtype.UUID{before.ClaimedByID, after.ClaimedByID} {
+		if claimer.Valid && claimer != parties[len(parties)-1] {
+			parties = append(parties, claimer)
+		}
+	}
+	return parties
+}
+
+// publishTaskEvent announces a committed task action to live subscribers.
+// Failures are logged rather than returned, since the change itself has
+// already been committed.
+func publishTaskEvent(ctx context.Context, before, after generated.Task, action tasks.Action) {
+	eventType, ok := taskEvents[action]
+	if !ok {
+		return
+	}
+
+	var audience []string
+	if privateTaskEvents[eventType] {
+		for _, party := range taskParties(before, after) {
+			audience = append(audience, utils.UUIDToString(party))
+		}
+	}
+
+	if err := events.Publish(ctx, eventType, models.ToTaskResponse(after), audience...); err != nil {
+		log.Printf("Failed to publish %s for task %s: %v", eventType, utils.UUIDToString(after.ID), err)
+	}
+}
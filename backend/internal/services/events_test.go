@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/egeuysall/summit/internal/events"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/utils"
+)
+
+// TestSystemTransitionsPublishEvents checks that transitions made outside a
+// request handler, by jobs and admins, reach live subscribers.
+func TestSystemTransitionsPublishEvents(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// run brings a task to the state the transition starts from and
+		// applies it, returning the task as it was read beforehand.
+		run        func(t *testing.T, db *testDB) generated.Task
+		wantType   string
+		wantPublic bool
+	}{
+		{"expire", func(t *testing.T, db *testDB) generated.Task {
+			task, _, _ := taskIn(t, "open")
+			if _, err := ExpireTask(ctx, task); err != nil {
+				t.Fatalf("ExpireTask: %v", err)
+			}
+			return task
+		}, events.TaskExpired, true},
+		{"expire claim", func(t *testing.T, db *testDB) generated.Task {
+			task, _, _ := taskIn(t, "claimed")
+			if _, err := ExpireClaim(ctx, task); err != nil {
+				t.Fatalf("ExpireClaim: %v", err)
+			}
+			return task
+		}, events.TaskReleased, true},
+		{"auto-confirm", func(t *testing.T, db *testDB) generated.Task {
+			task, _, _ := taskIn(t, "completed")
+			if _, err := AutoConfirmTask(ctx, task); err != nil {
+				t.Fatalf("AutoConfirmTask: %v", err)
+			}
+			return task
+		}, events.TaskConfirmed, false},
+		{"resolve dispute", func(t *testing.T, db *testDB) generated.Task {
+			task, requester, _ := taskIn(t, "completed")
+			dispute, err := OpenDispute(ctx, task, requester, "Not what was asked for")
+			if err != nil {
+				t.Fatalf("OpenDispute: %v", err)
+			}
+			task = db.task(t, task.ID)
+			if _, err := ResolveDispute(ctx, dispute, task, newProfile(t), OutcomeSplit, 50, ""); err != nil {
+				t.Fatalf("ResolveDispute: %v", err)
+			}
+			return task
+		}, events.TaskResolved, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			events.Init(events.NewMemoryBroker(16))
+			t.Cleanup(func() { events.Init(nil) })
+
+			subCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			stream, err := events.Subscribe(subCtx, 0)
+			if err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+
+			task := tt.run(t, db)
+			timeout := time.After(time.Second)
+			for {
+				var e events.Event
+				select {
+				case e = <-stream:
+				case <-timeout:
+					t.Fatalf("no %s event published", tt.wantType)
+				}
+				if e.Type != tt.wantType {
+					continue
+				}
+
+				var data struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(e.Data, &data); err != nil || data.ID != utils.UUIDToString(task.ID) {
+					t.Errorf("%s data = %s, want task %s", e.Type, e.Data, utils.UUIDToString(task.ID))
+				}
+				if public := len(e.Audience) == 0; public != tt.wantPublic {
+					t.Errorf("%s public = %v, want %v", e.Type, public, tt.wantPublic)
+				}
+				if !tt.wantPublic {
+					for _, party := range taskParties(task, task) {
+						if !slices.Contains(e.Audience, utils.UUIDToString(party)) {
+							t.Errorf("%s audience %v leaves out %s", e.Type, e.Audience, utils.UUIDToString(party))
+						}
+					}
+				}
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/egeuysall/summit/internal/events"
+	"github.com/egeuysall/summit/internal/models"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Transaction kinds recorded in the transactions ledger.
+const (
+	KindTaskEscrow      = "task_escrow"
+	KindTaskRefund      = "task_refund"
+	KindTaskPayout      = "task_payout"
+	KindSignupBonus     = "signup_bonus"
+	KindRedemption      = "redemption"
+	KindAdminAdjustment = "admin_adjustment"
+)
+
+// Ledger accounts. Every entry posts to a user account and the opposite
+// amount to the escrow or system account, so each entry sums to zero.
+const (
+	AccountUser   = "user"
+	AccountEscrow = "escrow"
+	AccountSystem = "system"
+)
+
+// SignupBonus is the number of credits granted to every new profile.
+const SignupBonus = 100
+
+// Reconciliation lists every place where balances, escrow holdings and the
+// ledger disagree, along with the total currently held in escrow.
+type Reconciliation struct {
+	BalanceMismatches []generated.GetBalanceMismatchesRow
+	UnbalancedEntries []generated.GetUnbalancedEntriesRow
+	EscrowMismatches  []generated.GetEscrowMismatchesRow
+	EscrowTotal       int32
+}
+
+// Balanced reports whether every profile balance equals the sum of its
+// ledger entries, every entry sums to zero and every task's escrow matches
+// both its reward and the escrow account.
+func (r Reconciliation) Balanced() bool {
+	return len(r.BalanceMismatches) == 0 && len(r.UnbalancedEntries) == 0 && len(r.EscrowMismatches) == 0
+}
+
+// Reconcile checks profiles.credits and task escrow against the ledger.
+func Reconcile(ctx context.Context) (Reconciliation, error) {
+	var report Reconciliation
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		var err error
+		report.BalanceMismatches, err = q.GetBalanceMismatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.UnbalancedEntries, err = q.GetUnbalancedEntries(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.EscrowMismatches, err = q.GetEscrowMismatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		report.EscrowTotal, err = q.GetEscrowTotal(ctx)
+		return err
+	})
+
+	return report, err
+}
+
+// credit adds amount to the user's balance and records the ledger entry.
+func credit(ctx context.Context, q *generated.Queries, userID, taskID pgtype.UUID, amount int32, kind string) (generated.Transaction, error) {
+	err := q.IncrementCredits(ctx, generated.IncrementCreditsParams{
+		ID:      userID,
+		Credits: pgtype.Int4{Int32: amount, Valid: true},
+	})
+	if err != nil {
+		return generated.Transaction{}, err
+	}
+
+	return post(ctx, q, userID, taskID, amount, kind)
+}
+
+// debit removes amount from the user's balance and records the ledger entry.
+// It returns ErrInsufficientCredits if the balance would go negative.
+func debit(ctx context.Context, q *generated.Queries, userID, taskID pgtype.UUID, amount int32, kind string) (generated.Transaction, error) {
+	_, err := q.DecrementCredits(ctx, generated.DecrementCreditsParams{
+		ID:      userID,
+		Credits: pgtype.Int4{Int32: amount, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.Transaction{}, ErrInsufficientCredits
+	}
+	if err != nil {
+		return generated.Transaction{}, err
+	}
+
+	return post(ctx, q, userID, taskID, -amount, kind)
+}
+
+// post writes both sides of a ledger entry, notifies the user, queues
+// webhooks and returns the user's side.
+func post(ctx context.Context, q *generated.Queries, userID, taskID pgtype.UUID, credits int32, kind string) (generated.Transaction, error) {
+	entryID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+	transaction, err := q.CreateTransaction(ctx, generated.CreateTransactionParams{
+		UserID:  userID,
+		TaskID:  taskID,
+		Credits: credits,
+		Kind:    kind,
+		Account: AccountUser,
+		EntryID: entryID,
+	})
+	if err != nil {
+		return generated.Transaction{}, err
+	}
+
+	_, err = q.CreateTransaction(ctx, generated.CreateTransactionParams{
+		TaskID:  taskID,
+		Credits: -credits,
+		Kind:    kind,
+		Account: counterAccount(kind),
+		EntryID: entryID,
+	})
+	if err != nil {
+		return generated.Transaction{}, err
+	}
+
+	if err := notifyCredits(ctx, q, userID, taskID, credits, kind); err != nil {
+		return generated.Transaction{}, err
+	}
+
+	return transaction, enqueueWebhooks(ctx, q, events.LedgerTransaction, models.ToTransactionResponse(transaction), userID)
+}
+
+func counterAccount(kind string) string {
+	switch kind {
+	case KindTaskEscrow, KindTaskRefund, KindTaskPayout:
+		return AccountEscrow
+	}
+	return AccountSystem
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrNoConversation     = errors.New("task has not been claimed")
+	ErrConversationClosed = errors.New("task conversation is closed")
+)
+
+// conversationStatuses are the statuses in which the parties may still send
+// messages. Earlier messages stay readable after the task is confirmed.
+var conversationStatuses = []tasks.Status{tasks.Claimed, tasks.Completed, tasks.Disputed}
+
+// ConversationClaimer returns the claimer whose conversation on task userID
+// may read. Each claim has its own conversation: the requester reads the
+// current claimer's, or the latest one once the task has no claimer, and a
+// claimer reads their own, including after the claim ended. Access rests on
+// who took part rather than on the task's claimer, which cancelling and
+// releasing clear.
+func ConversationClaimer(ctx context.Context, task generated.Task, userID pgtype.UUID) (pgtype.UUID, error) {
+	var claimerID pgtype.UUID
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		var err error
+		claimerID, err = conversationClaimer(ctx, q, task, userID)
+		return err
+	})
+
+	return claimerID, err
+}
+
+func conversationClaimer(ctx context.Context, q *generated.Queries, task generated.Task, userID pgtype.UUID) (pgtype.UUID, error) {
+	role := tasks.RoleOf(task, userID)
+	if task.ClaimedByID.Valid && (role == tasks.Requester || role == tasks.Claimer) {
+		return task.ClaimedByID, nil
+	}
+
+	arg := generated.GetLatestThreadClaimerParams{TaskID: task.ID}
+	if role != tasks.Requester {
+		arg.ClaimerID = userID
+	}
+
+	claimerID, err := q.GetLatestThreadClaimer(ctx, arg)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows) && role == tasks.Requester:
+		return pgtype.UUID{}, ErrNoConversation
+	case errors.Is(err, pgx.ErrNoRows):
+		return pgtype.UUID{}, ErrNotTaskParty
+	}
+	return claimerID, err
+}
+
+// SendTaskMessage posts a message from senderID to the other party on task.
+// Only the current claim's conversation is open.
+func SendTaskMessage(ctx context.Context, task generated.Task, senderID pgtype.UUID, body string) (generated.TaskMessage, error) {
+	var message generated.TaskMessage
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		claimerID, err := conversationClaimer(ctx, q, task, senderID)
+		if err != nil {
+			return err
+		}
+		if claimerID != task.ClaimedByID || !slices.Contains(conversationStatuses, tasks.StatusOf(task)) {
+			return ErrConversationClosed
+		}
+
+		message, err = q.CreateTaskMessage(ctx, generated.CreateTaskMessageParams{
+			TaskID:    task.ID,
+			ClaimerID: claimerID,
+			SenderID:  senderID,
+			Body:      body,
+		})
+		return err
+	})
+
+	return message, err
+}
+
+// MarkTaskMessagesRead marks the other party's messages in readerID's
+// conversation on task, up to and including messageID, as read. It returns
+// the messages that were newly marked.
+func MarkTaskMessagesRead(ctx context.Context, task generated.Task, readerID, messageID pgtype.UUID) ([]generated.TaskMessage, error) {
+	var messages []generated.TaskMessage
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		claimerID, err := conversationClaimer(ctx, q, task, readerID)
+		if err != nil {
+			return err
+		}
+
+		messages, err = q.MarkTaskMessagesRead(ctx, generated.MarkTaskMessagesReadParams{
+			TaskID:    task.ID,
+			ClaimerID: claimerID,
+			ReaderID:  readerID,
+			MessageID: messageID,
+		})
+		return err
+	})
+
+	return messages, err
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TestConversationOutlivesClaim checks that cancelling or releasing a task,
+// which clears its claimer, leaves the parties able to read what they said.
+func TestConversationOutlivesClaim(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		end  func(task generated.Task, requester, claimer pgtype.UUID) (generated.Task, error)
+	}{
+		{"cancel", func(task generated.Task, requester, claimer pgtype.UUID) (generated.Task, error) {
+			return CancelTask(ctx, task, requester)
+		}},
+		{"release", func(task generated.Task, requester, claimer pgtype.UUID) (generated.Task, error) {
+			return ReleaseTask(ctx, task, claimer)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestDB(t)
+
+			task, requester, claimer := taskIn(t, "claimed")
+			if _, err := SendTaskMessage(ctx, task, claimer, "On it"); err != nil {
+				t.Fatalf("SendTaskMessage: %v", err)
+			}
+
+			task, err := tt.end(task, requester, claimer)
+			if err != nil {
+				t.Fatalf("ending claim: %v", err)
+			}
+
+			for name, userID := range map[string]pgtype.UUID{"requester": requester, "claimer": claimer} {
+				got, err := ConversationClaimer(ctx, task, userID)
+				if err != nil || got != claimer {
+					t.Errorf("%s: ConversationClaimer = %v, %v; want the claimer's conversation", name, got, err)
+				}
+			}
+
+			if _, err := ConversationClaimer(ctx, task, newProfile(t)); !errors.Is(err, ErrNotTaskParty) {
+				t.Errorf("stranger: ConversationClaimer error = %v, want %v", err, ErrNotTaskParty)
+			}
+			if _, err := SendTaskMessage(ctx, task, requester, "Still there?"); !errors.Is(err, ErrConversationClosed) {
+				t.Errorf("SendTaskMessage after the claim ended: error = %v, want %v", err, ErrConversationClosed)
+			}
+		})
+	}
+}
+
+func TestConversationNeedsClaim(t *testing.T) {
+	newTestDB(t)
+
+	task, requester, _ := taskIn(t, "open")
+	if _, err := ConversationClaimer(context.Background(), task, requester); !errors.Is(err, ErrNoConversation) {
+		t.Errorf("ConversationClaimer error = %v, want %v", err, ErrNoConversation)
+	}
+}
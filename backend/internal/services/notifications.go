@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Notification types. Users can turn each one off in their preferences.
+const (
+	NotifyTaskClaimed    = "task_claimed"
+	NotifyTaskCompleted  = "task_completed"
+	NotifyTaskConfirmed  = "task_confirmed"
+	NotifyTaskCancelled  = "task_cancelled"
+	NotifyCreditsChanged = "credits_changed"
+)
+
+// NotificationTypes lists every notification type, in the order they are
+// shown in preferences.
+var NotificationTypes = []string{
+	NotifyTaskClaimed,
+	NotifyTaskCompleted,
+	NotifyTaskConfirmed,
+	NotifyTaskCancelled,
+	NotifyCreditsChanged,
+}
+
+// Email types. Users can turn each one off in their preferences or through
+// the unsubscribe link in the email itself.
+const (
+	EmailTaskClaimed   = "email_" + NotifyTaskClaimed
+	EmailTaskCompleted = "email_" + NotifyTaskCompleted
+	EmailDigest        = "email_digest"
+)
+
+// EmailTypes lists every email type, in the order they are shown in
+// preferences.
+var EmailTypes = []string{
+	EmailTaskClaimed,
+	EmailTaskCompleted,
+	EmailDigest,
+}
+
+// EmailedNotifications are the notification types that are also sent by
+// email, under the email type "email_" + the notification type. Turning the
+// notification itself off stops the email too.
+var EmailedNotifications = []string{
+	NotifyTaskClaimed,
+	NotifyTaskCompleted,
+}
+
+// notify adds a notification to the user's inbox unless they have turned
+// its type off.
+func notify(ctx context.Context, q *generated.Queries, userID pgtype.UUID, kind string, taskID pgtype.UUID, message string) error {
+	return q.CreateNotification(ctx, generated.CreateNotificationParams{
+		Type:    kind,
+		TaskID:  taskID,
+		Message: message,
+		UserID:  userID,
+	})
+}
+
+// notifyTransition tells the other party to a task about a status change.
+// before is the task as it was read, since some transitions clear the claimer.
+func notifyTransition(ctx context.Context, q *generated.Queries, before, after generated.Task, action tasks.Action) error {
+	switch action {
+	case tasks.Claim:
+		return notify(ctx, q, after.RequesterID, NotifyTaskClaimed, after.ID,
+			fmt.Sprintf("Your task %q was claimed", after.Title))
+	case tasks.Complete:
+		return notify(ctx, q, after.RequesterID, NotifyTaskCompleted, after.ID,
+			fmt.Sprintf("%q was completed and is waiting for your confirmation", after.Title))
+	case tasks.Confirm, tasks.AutoConfirm:
+		return notify(ctx, q, after.ClaimedByID, NotifyTaskConfirmed, after.ID,
+			fmt.Sprintf("Your work on %q was confirmed", after.Title))
+	case tasks.Cancel:
+		if !before.ClaimedByID.Valid {
+			return nil
+		}
+		return notify(ctx, q, before.ClaimedByID, NotifyTaskCancelled, after.ID,
+			fmt.Sprintf("%q was cancelled by its requester", after.Title))
+	}
+	return nil
+}
+
+// notifyCredits tells a user their balance changed by credits.
+func notifyCredits(ctx context.Context, q *generated.Queries, userID, taskID pgtype.UUID, credits int32, kind string) error {
+	var message string
+	switch kind {
+	case KindTaskEscrow:
+		message = fmt.Sprintf("%d credits were placed in escrow for your task", -credits)
+	case KindTaskRefund:
+		message = fmt.Sprintf("%d credits were refunded from escrow", credits)
+	case KindTaskPayout:
+		message = fmt.Sprintf("You earned %d credits", credits)
+	case KindSignupBonus:
+		message = fmt.Sprintf("You received a %d credit signup bonus", credits)
+	case KindRedemption:
+		message = fmt.Sprintf("You spent %d credits on a reward", -credits)
+	default:
+		message = fmt.Sprintf("Your balance changed by %+d credits", credits)
+	}
+
+	return notify(ctx, q, userID, NotifyCreditsChanged, taskID, message)
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateProfile creates a profile with an empty balance and grants the
+// signup bonus through the ledger.
+func CreateProfile(ctx context.Context, arg generated.CreateProfileParams) (generated.Profile, error) {
+	var profile generated.Profile
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		arg.Credits = pgtype.Int4{Int32: 0, Valid: true}
+
+		var err error
+		profile, err = q.CreateProfile(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if _, err := credit(ctx, q, profile.ID, pgtype.UUID{}, SignupBonus, KindSignupBonus); err != nil {
+			return err
+		}
+
+		profile.Credits.Int32 = SignupBonus
+		return nil
+	})
+
+	return profile, err
+}
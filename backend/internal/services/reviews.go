@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrNotReviewable   = errors.New("only confirmed tasks can be reviewed")
+	ErrNotTaskParty    = errors.New("user is not the requester or claimer of the task")
+	ErrAlreadyReviewed = errors.New("task already reviewed")
+)
+
+// CreateReview records reviewerID's rating of the other party to a confirmed
+// task. Each party may review a task once.
+func CreateReview(ctx context.Context, task generated.Task, reviewerID pgtype.UUID, rating int32, body string) (generated.Review, error) {
+	if tasks.StatusOf(task) != tasks.Confirmed {
+		return generated.Review{}, ErrNotReviewable
+	}
+
+	var revieweeID pgtype.UUID
+	switch tasks.RoleOf(task, reviewerID) {
+	case tasks.Requester:
+		revieweeID = task.ClaimedByID
+	case tasks.Claimer:
+		revieweeID = task.RequesterID
+	default:
+		return generated.Review{}, ErrNotTaskParty
+	}
+
+	var review generated.Review
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		var err error
+		review, err = q.CreateReview(ctx, generated.CreateReviewParams{
+			TaskID:     task.ID,
+			ReviewerID: reviewerID,
+			RevieweeID: revieweeID,
+			Rating:     rating,
+			Body:       body,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAlreadyReviewed
+		}
+		return err
+	})
+
+	return review, err
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RedeemReward debits the reward's cost from the user and records the redemption.
+func RedeemReward(ctx context.Context, userID pgtype.UUID, reward generated.Reward) (generated.Redemption, error) {
+	var redemption generated.Redemption
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		transaction, err := debit(ctx, q, userID, pgtype.UUID{}, reward.Cost, KindRedemption)
+		if err != nil {
+			return err
+		}
+
+		redemption, err = q.CreateRedemption(ctx, generated.CreateRedemptionParams{
+			UserID:        userID,
+			RewardID:      reward.ID,
+			TransactionID: transaction.ID,
+			Cost:          reward.Cost,
+		})
+		return err
+	})
+
+	return redemption, err
+}
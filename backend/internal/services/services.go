@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	ErrEscrowSettled       = errors.New("task escrow already settled")
+	ErrTaskConflict        = errors.New("task was changed by another request")
+)
+
+// TrustError is returned when a user's trust level is below what an action needs.
+type TrustError struct {
+	Required tasks.TrustLevel
+}
+
+func (e *TrustError) Error() string {
+	return fmt.Sprintf("requires trust level %s", e.Required)
+}
+
+// TxBeginner is the subset of *pgxpool.Pool used to open transactions.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var db TxBeginner
+
+func Init(pool TxBeginner) {
+	db = pool
+}
+
+// WithTx runs fn inside a single database transaction. The transaction is
+// committed only if fn returns nil; any error rolls back every statement fn ran.
+func WithTx(ctx context.Context, fn func(q *generated.Queries) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(generated.New(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestWithTxCommitsOnSuccess(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	user := newProfile(t)
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		_, err := credit(ctx, q, user, pgtype.UUID{}, 25, KindAdminAdjustment)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	if got := db.balance(t, user); got != SignupBonus+25 {
+		t.Errorf("balance = %d, want %d", got, SignupBonus+25)
+	}
+	assertBalanced(t)
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	user := newProfile(t)
+	before := db.snapshot(t)
+
+	failure := errors.New("step failed")
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		if _, err := credit(ctx, q, user, pgtype.UUID{}, 25, KindAdminAdjustment); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("WithTx error = %v, want %v", err, failure)
+	}
+
+	if !reflect.DeepEqual(db.snapshot(t), before) {
+		t.Error("rolled back transaction left changes behind")
+	}
+	assertBalanced(t)
+}
+
+// newProfile creates a profile holding the signup bonus.
+func newProfile(t *testing.T) pgtype.UUID {
+	t.Helper()
+
+	profile, err := CreateProfile(context.Background(), generated.CreateProfileParams{
+		ID:   newID(),
+		Name: "Test user",
+	})
+	if err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	return profile.ID
+}
+
+// assertBalanced fails the test unless the ledger reconciles and every
+// credit issued through the system account is in a balance or in escrow.
+func assertBalanced(t *testing.T) {
+	t.Helper()
+
+	report, err := Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if !report.Balanced() {
+		t.Fatalf("ledger out of balance: %+v", report)
+	}
+
+	var issued, held int32
+	err = testPool.QueryRow(context.Background(), `
+		SELECT
+		  (SELECT COALESCE(-SUM(credits), 0)::INTEGER FROM transactions WHERE account = 'system'),
+		  (SELECT COALESCE(SUM(credits), 0)::INTEGER FROM profiles)`).Scan(&issued, &held)
+	if err != nil {
+		t.Fatalf("totalling credits: %v", err)
+	}
+
+	if total := held + report.EscrowTotal; total != issued {
+		t.Fatalf("balances plus escrow = %d credits, but %d were issued", total, issued)
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateTask creates a task and moves its reward, plus any urgency premium,
+// from the requester into escrow.
+func CreateTask(ctx context.Context, arg generated.CreateTaskParams) (generated.Task, error) {
+	var task generated.Task
+
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		var err error
+		task, err = q.CreateTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if _, err := debit(ctx, q, task.RequesterID, task.ID, task.EscrowAmount, KindTaskEscrow); err != nil {
+			return err
+		}
+
+		if err := recordTransition(ctx, q, task.ID, tasks.Create, "", tasks.StatusOf(task), task.RequesterID); err != nil {
+			return err
+		}
+
+		return enqueueTaskWebhooks(ctx, q, task, task, tasks.Create)
+	})
+	if err != nil {
+		return task, err
+	}
+
+	publishTaskEvent(ctx, task, task, tasks.Create)
+	return task, nil
+}
+
+// DeleteTask deletes the task and refunds the requester from escrow. The
+// delete only matches the task while it is still open; if it was claimed
+// since it was read, DeleteTask returns ErrTaskConflict and refunds nothing.
+func DeleteTask(ctx context.Context, task generated.Task, actorID pgtype.UUID) error {
+	if _, err := tasks.Next(tasks.StatusOf(task), tasks.Delete, tasks.RoleOf(task, actorID)); err != nil {
+		return err
+	}
+
+	return WithTx(ctx, func(q *generated.Queries) error {
+		deleted, err := q.DeleteTask(ctx, task.ID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		return refundDeletedEscrow(ctx, q, deleted)
+	})
+}
+
+// ClaimTask assigns an open task to the claimer. High-reward tasks require
+// the claimer to have reached tasks.RequiredTrustLevel.
+func ClaimTask(ctx context.Context, task generated.Task, claimerID pgtype.UUID) (generated.Task, error) {
+	return transition(ctx, task, tasks.Claim, claimerID, func(q *generated.Queries) (generated.Task, error) {
+		if required := tasks.RequiredTrustLevel(task.CreditReward); required > tasks.TrustNew {
+			rep, err := q.GetUserReputation(ctx, claimerID)
+			if err != nil {
+				return generated.Task{}, err
+			}
+			if tasks.TrustLevelFor(rep.Score) < required {
+				return generated.Task{}, &TrustError{Required: required}
+			}
+		}
+
+		return q.ClaimTask(ctx, generated.ClaimTaskParams{
+			ID:          task.ID,
+			ClaimedByID: claimerID,
+		})
+	})
+}
+
+// ReleaseTask returns a claimed task to the open pool on behalf of its claimer.
+func ReleaseTask(ctx context.Context, task generated.Task, actorID pgtype.UUID) (generated.Task, error) {
+	return transition(ctx, task, tasks.Release, actorID, func(q *generated.Queries) (generated.Task, error) {
+		return q.ReleaseTask(ctx, generated.ReleaseTaskParams{
+			ID:          task.ID,
+			ClaimedByID: actorID,
+		})
+	})
+}
+
+// CompleteTask marks a claimed task as completed.
+func CompleteTask(ctx context.Context, task generated.Task, actorID pgtype.UUID) (generated.Task, error) {
+	return transition(ctx, task, tasks.Complete, actorID, func(q *generated.Queries) (generated.Task, error) {
+		return q.CompleteTask(ctx, task.ID)
+	})
+}
+
+// RejectTask sends completed work back to the claimer with the requester's
+// reason. After tasks.MaxRejections rejections the task becomes disputed.
+func RejectTask(ctx context.Context, task generated.Task, actorID pgtype.UUID, reason string) (generated.Task, error) {
+	return transition(ctx, task, tasks.Reject, actorID, func(q *generated.Queries) (generated.Task, error) {
+		rejected, err := q.RejectTask(ctx, generated.RejectTaskParams{
+			MaxRejections:   tasks.MaxRejections,
+			RejectionReason: pgtype.Text{String: reason, Valid: true},
+			ID:              task.ID,
+		})
+		if err != nil || tasks.StatusOf(rejected) != tasks.Disputed {
+			return rejected, err
+		}
+
+		_, err = q.CreateDispute(ctx, generated.CreateDisputeParams{
+			TaskID:     task.ID,
+			OpenedByID: actorID,
+			Reason:     reason,
+		})
+		return rejected, err
+	})
+}
+
+// ConfirmTask marks the task confirmed and pays its reward from escrow to the claimer.
+func ConfirmTask(ctx context.Context, task generated.Task, actorID pgtype.UUID) (generated.Task, error) {
+	return confirm(ctx, task, tasks.Confirm, actorID)
+}
+
+// AutoConfirmTask confirms a task on the system's behalf through the same
+// path as ConfirmTask, recording the confirmation as automatic.
+func AutoConfirmTask(ctx context.Context, task generated.Task) (generated.Task, error) {
+	return confirm(ctx, task, tasks.AutoConfirm, pgtype.UUID{})
+}
+
+func confirm(ctx context.Context, task generated.Task, action tasks.Action, actorID pgtype.UUID) (generated.Task, error) {
+	return transition(ctx, task, action, actorID, func(q *generated.Queries) (generated.Task, error) {
+		confirmed, err := q.ConfirmTask(ctx, task.ID)
+		if err != nil {
+			return confirmed, err
+		}
+
+		return confirmed, releaseEscrow(ctx, q, confirmed)
+	})
+}
+
+// CancelTask marks the task cancelled and refunds the requester from escrow.
+func CancelTask(ctx context.Context, task generated.Task, actorID pgtype.UUID) (generated.Task, error) {
+	return transition(ctx, task, tasks.Cancel, actorID, func(q *generated.Queries) (generated.Task, error) {
+		cancelled, err := q.CancelTask(ctx, generated.CancelTaskParams{
+			ID:     task.ID,
+			Status: task.Status,
+		})
+		if err != nil {
+			return cancelled, err
+		}
+
+		return cancelled, refundEscrow(ctx, q, cancelled)
+	})
+}
+
+// ExpireTask closes an open task past its deadline and refunds the requester
+// from escrow.
+func ExpireTask(ctx context.Context, task generated.Task) (generated.Task, error) {
+	return transition(ctx, task, tasks.Expire, pgtype.UUID{}, func(q *generated.Queries) (generated.Task, error) {
+		expired, err := q.ExpireTask(ctx, task.ID)
+		if err != nil {
+			return expired, err
+		}
+
+		return expired, refundEscrow(ctx, q, expired)
+	})
+}
+
+// ExpireClaim returns a claimed task past its deadline to the open pool. The
+// deadline is cleared with the claim, since it was the claimer who missed it.
+func ExpireClaim(ctx context.Context, task generated.Task) (generated.Task, error) {
+	return transition(ctx, task, tasks.ExpireClaim, pgtype.UUID{}, func(q *generated.Queries) (generated.Task, error) {
+		return q.ExpireClaim(ctx, generated.ExpireClaimParams{
+			ID:          task.ID,
+			ClaimedByID: task.ClaimedByID,
+		})
+	})
+}
+
+// transition checks that actorID may perform action on task, then runs
+// update and records the status change in one transaction, publishing the
+// action's event once it commits. update must only match the task while it
+// is still in the status it was read with; if it matches no rows,
+// transition returns ErrTaskConflict.
+func transition(ctx context.Context, task generated.Task, action tasks.Action, actorID pgtype.UUID, update func(q *generated.Queries) (generated.Task, error)) (generated.Task, error) {
+	return transitionAs(ctx, task, action, tasks.RoleOf(task, actorID), actorID, update)
+}
+
+// transitionAs is transition for actors whose role does not follow from the
+// task itself, such as admins.
+func transitionAs(ctx context.Context, task generated.Task, action tasks.Action, role tasks.Role, actorID pgtype.UUID, update func(q *generated.Queries) (generated.Task, error)) (generated.Task, error) {
+	from := tasks.StatusOf(task)
+
+	if _, err := tasks.Next(from, action, role); err != nil {
+		return generated.Task{}, err
+	}
+
+	var updated generated.Task
+	err := WithTx(ctx, func(q *generated.Queries) error {
+		var err error
+		updated, err = update(q)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := recordTransition(ctx, q, task.ID, action, from, tasks.StatusOf(updated), actorID); err != nil {
+			return err
+		}
+
+		if err := notifyTransition(ctx, q, task, updated, action); err != nil {
+			return err
+		}
+
+		return enqueueTaskWebhooks(ctx, q, task, updated, action)
+	})
+	if err != nil {
+		return updated, err
+	}
+
+	publishTaskEvent(ctx, task, updated, action)
+	return updated, nil
+}
+
+func recordTransition(ctx context.Context, q *generated.Queries, taskID pgtype.UUID, action tasks.Action, from, to tasks.Status, actorID pgtype.UUID) error {
+	_, err := q.CreateTaskStatusHistory(ctx, generated.CreateTaskStatusHistoryParams{
+		TaskID:     taskID,
+		Action:     string(action),
+		FromStatus: pgtype.Text{String: string(from), Valid: from != ""},
+		ToStatus:   string(to),
+		ActorID:    actorID,
+	})
+	return err
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TestLifecycleFailuresLoseNoCredits fails each statement of every lifecycle
+// step in turn and checks that the step leaves no trace: no credits created
+// or lost, nothing half-written, and the ledger still reconciles.
+func TestLifecycleFailuresLoseNoCredits(t *testing.T) {
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		// setup brings a task to the state the step starts from and returns
+		// the step.
+		setup func(t *testing.T) func() error
+	}{
+		{"create profile", func(t *testing.T) func() error {
+			id := newID()
+			return func() error {
+				_, err := CreateProfile(ctx, generated.CreateProfileParams{ID: id, Name: "New user"})
+				return err
+			}
+		}},
+		{"create task", func(t *testing.T) func() error {
+			requester := newProfile(t)
+			return func() error {
+				_, err := CreateTask(ctx, taskParams(requester, 40))
+				return err
+			}
+		}},
+		{"delete", func(t *testing.T) func() error {
+			task, requester, _ := taskIn(t, "open")
+			return func() error { return DeleteTask(ctx, task, requester) }
+		}},
+		{"claim", func(t *testing.T) func() error {
+			task, _, claimer := taskIn(t, "open")
+			return func() error {
+				_, err := ClaimTask(ctx, task, claimer)
+				return err
+			}
+		}},
+		{"release", func(t *testing.T) func() error {
+			task, _, claimer := taskIn(t, "claimed")
+			return func() error {
+				_, err := ReleaseTask(ctx, task, claimer)
+				return err
+			}
+		}},
+		{"complete", func(t *testing.T) func() error {
+			task, _, claimer := taskIn(t, "claimed")
+			return func() error {
+				_, err := CompleteTask(ctx, task, claimer)
+				return err
+			}
+		}},
+		{"confirm", func(t *testing.T) func() error {
+			task, requester, _ := taskIn(t, "completed")
+			return func() error {
+				_, err := ConfirmTask(ctx, task, requester)
+				return err
+			}
+		}},
+		{"auto-confirm", func(t *testing.T) func() error {
+			task, _, _ := taskIn(t, "completed")
+			return func() error {
+				_, err := AutoConfirmTask(ctx, task)
+				return err
+			}
+		}},
+		{"cancel open", func(t *testing.T) func() error {
+			task, requester, _ := taskIn(t, "open")
+			return func() error {
+				_, err := CancelTask(ctx, task, requester)
+				return err
+			}
+		}},
+		{"cancel claimed", func(t *testing.T) func() error {
+			task, requester, _ := taskIn(t, "claimed")
+			return func() error {
+				_, err := CancelTask(ctx, task, requester)
+				return err
+			}
+		}},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			db := newTestDB(t)
+			run := step.setup(t)
+
+			for n := 1; ; n++ {
+				if n > 100 {
+					t.Fatal("step never succeeded")
+				}
+
+				before := db.snapshot(t)
+				db.failNth(n)
+
+				err := run()
+				if err == nil {
+					break
+				}
+				if !errors.Is(err, errInjected) {
+					t.Fatalf("statement %d: unexpected error: %v", n, err)
+				}
+
+				if !reflect.DeepEqual(db.snapshot(t), before) {
+					t.Fatalf("failing statement %d left partial changes", n)
+				}
+				assertBalanced(t)
+			}
+
+			db.failNth(0)
+			assertBalanced(t)
+		})
+	}
+}
+
+// TestConcurrentClaimsHaveOneWinner fires many claims at one open task at
+// once, each working from the same stale read, and checks that exactly one
+// wins and the rest get ErrTaskConflict.
+func TestConcurrentClaimsHaveOneWinner(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	task, _, _ := taskIn(t, "open")
+
+	const claimers = 25
+	ids := make([]pgtype.UUID, claimers)
+	for i := range ids {
+		ids[i] = newProfile(t)
+	}
+
+	errs := race(claimers, func(i int) error {
+		_, err := ClaimTask(ctx, task, ids[i])
+		return err
+	})
+
+	winner := -1
+	for i, err := range errs {
+		switch {
+		case err == nil && winner >= 0:
+			t.Fatalf("claimers %d and %d both won", winner, i)
+		case err == nil:
+			winner = i
+		case !errors.Is(err, ErrTaskConflict):
+			t.Fatalf("claimer %d: got %v, want ErrTaskConflict", i, err)
+		}
+	}
+	if winner < 0 {
+		t.Fatal("no claim won")
+	}
+
+	claimed := db.task(t, task.ID)
+	if claimed.Status.String != "claimed" || claimed.ClaimedByID != ids[winner] {
+		t.Errorf("task is %s by %v, want claimed by the winner", claimed.Status.String, claimed.ClaimedByID)
+	}
+	assertBalanced(t)
+}
+
+// TestConcurrentConfirmsPayOnce confirms one completed task many times at
+// once, by hand and automatically, and checks the claimer is paid once.
+func TestConcurrentConfirmsPayOnce(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	task, requester, claimer := taskIn(t, "completed")
+
+	errs := race(20, func(i int) error {
+		var err error
+		if i%2 == 0 {
+			_, err = ConfirmTask(ctx, task, requester)
+		} else {
+			_, err = AutoConfirmTask(ctx, task)
+		}
+		return err
+	})
+
+	wins := 0
+	for i, err := range errs {
+		if err == nil {
+			wins++
+		} else if !errors.Is(err, ErrTaskConflict) {
+			t.Fatalf("confirm %d: got %v, want ErrTaskConflict", i, err)
+		}
+	}
+	if wins != 1 {
+		t.Fatalf("%d confirms won, want 1", wins)
+	}
+
+	if got, want := db.balance(t, claimer), SignupBonus+task.CreditReward; got != want {
+		t.Errorf("claimer balance = %d, want %d", got, want)
+	}
+	assertBalanced(t)
+}
+
+// TestDeleteAfterClaimConflicts deletes a task from a read taken before it
+// was claimed. The delete must fail without refunding, so the claimer can
+// still be paid.
+func TestDeleteAfterClaimConflicts(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	stale, requester, claimer := taskIn(t, "open")
+
+	claimed, err := ClaimTask(ctx, stale, claimer)
+	if err != nil {
+		t.Fatalf("ClaimTask: %v", err)
+	}
+
+	if err := DeleteTask(ctx, stale, requester); !errors.Is(err, ErrTaskConflict) {
+		t.Fatalf("DeleteTask: got %v, want ErrTaskConflict", err)
+	}
+	if got, want := db.balance(t, requester), SignupBonus-stale.EscrowAmount; got != want {
+		t.Errorf("requester balance = %d, want %d", got, want)
+	}
+
+	completed, err := CompleteTask(ctx, claimed, claimer)
+	if err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	if _, err := ConfirmTask(ctx, completed, requester); err != nil {
+		t.Fatalf("ConfirmTask: %v", err)
+	}
+	if got, want := db.balance(t, claimer), SignupBonus+stale.CreditReward; got != want {
+		t.Errorf("claimer balance = %d, want %d", got, want)
+	}
+	assertBalanced(t)
+}
+
+// TestConcurrentDeleteAndClaim races a delete against a claim on the same
+// open task. Exactly one may win, and credits must balance either way.
+func TestConcurrentDeleteAndClaim(t *testing.T) {
+	newTestDB(t)
+	ctx := context.Background()
+
+	for range 20 {
+		task, requester, claimer := taskIn(t, "open")
+
+		errs := race(2, func(i int) error {
+			if i == 0 {
+				return DeleteTask(ctx, task, requester)
+			}
+			_, err := ClaimTask(ctx, task, claimer)
+			return err
+		})
+
+		if (errs[0] == nil) == (errs[1] == nil) {
+			t.Fatalf("delete: %v, claim: %v; want exactly one to win", errs[0], errs[1])
+		}
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, ErrTaskConflict) {
+				t.Fatalf("got %v, want ErrTaskConflict", err)
+			}
+		}
+		assertBalanced(t)
+	}
+}
+
+// race runs n calls of fn at once and returns their errors by index.
+func race(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+func taskParams(requester pgtype.UUID, reward int32) generated.CreateTaskParams {
+	return generated.CreateTaskParams{
+		Title:        "Test task",
+		Description:  "Something to do",
+		Skill:        "copywriting",
+		Urgency:      "medium",
+		CreditReward: reward,
+		RequesterID:  requester,
+	}
+}
+
+// taskIn creates a task and drives it to status, returning it with its
+// requester and claimer.
+func taskIn(t *testing.T, status string) (task generated.Task, requester, claimer pgtype.UUID) {
+	t.Helper()
+	ctx := context.Background()
+
+	requester, claimer = newProfile(t), newProfile(t)
+
+	task, err := CreateTask(ctx, taskParams(requester, 40))
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	for _, step := range []struct {
+		status string
+		run    func() (generated.Task, error)
+	}{
+		{"claimed", func() (generated.Task, error) { return ClaimTask(ctx, task, claimer) }},
+		{"completed", func() (generated.Task, error) { return CompleteTask(ctx, task, claimer) }},
+	} {
+		if task.Status.String == status {
+			break
+		}
+		if task, err = step.run(); err != nil {
+			t.Fatalf("moving task to %s: %v", step.status, err)
+		}
+	}
+
+	if task.Status.String != status {
+		t.Fatalf("task status = %s, want %s", task.Status.String, status)
+	}
+	return task, requester, claimer
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The services tests run against Postgres, since what they check (rollback,
+// row locking and the conditional updates that settle races) lives in the
+// SQL. Set TEST_DATABASE_URL to a server the tests may create databases on;
+// each run builds a scratch database from the migrations and drops it
+// afterwards. Without it, tests that need the database are skipped.
+
+// errInjected is returned by the statement a test chose to fail.
+var errInjected = errors.New("injected failure")
+
+// testPool is connected to the scratch database, or nil when
+// TEST_DATABASE_URL is unset.
+var testPool *pgxpool.Pool
+
+// authStub stands in for the parts of Supabase's auth schema that the
+// migrations refer to.
+const authStub = `
+CREATE SCHEMA auth;
+CREATE TABLE auth.users (id UUID PRIMARY KEY, email TEXT);
+CREATE FUNCTION auth.uid() RETURNS UUID LANGUAGE sql STABLE AS $$ SELECT NULL::UUID $$;
+`
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		return m.Run()
+	}
+
+	ctx := context.Background()
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connecting to TEST_DATABASE_URL: %v\n", err)
+		return 1
+	}
+	defer admin.Close(ctx)
+
+	name := fmt.Sprintf("summit_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		fmt.Fprintf(os.Stderr, "creating test database: %v\n", err)
+		return 1
+	}
+	defer admin.Exec(ctx, "DROP DATABASE "+name+" WITH (FORCE)")
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parsing TEST_DATABASE_URL: %v\n", err)
+		return 1
+	}
+	config.ConnConfig.Database = name
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connecting to test database: %v\n", err)
+		return 1
+	}
+	defer pool.Close()
+
+	if err := migrate(ctx, pool); err != nil {
+		fmt.Fprintf(os.Stderr, "migrating test database: %v\n", err)
+		return 1
+	}
+
+	testPool = pool
+	return m.Run()
+}
+
+// migrate applies the repo's migrations in order.
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, authStub); err != nil {
+		return fmt.Errorf("auth stub: %w", err)
+	}
+
+	paths, err := filepath.Glob("../supabase/migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// testDB is the services database during a test: the scratch database,
+// with a hook to make one statement fail.
+type testDB struct {
+	pool *pgxpool.Pool
+
+	mu sync.Mutex
+	// failAt is the statement, counted from the last failNth call, that
+	// returns errInjected. Zero disables injection.
+	failAt     int
+	statements int
+}
+
+// newTestDB empties the scratch database and installs it as the services
+// database, skipping the test if there is none.
+func newTestDB(t *testing.T) *testDB {
+	t.Helper()
+
+	if testPool == nil {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	if _, err := testPool.Exec(context.Background(), "TRUNCATE profiles CASCADE"); err != nil {
+		t.Fatalf("emptying test database: %v", err)
+	}
+
+	db := &testDB{pool: testPool}
+	Init(db)
+	return db
+}
+
+// failNth makes the nth statement from now fail.
+func (db *testDB) failNth(n int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.failAt, db.statements = n, 0
+}
+
+// fail counts a statement and reports whether it is the one to fail.
+func (db *testDB) fail() bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.statements++
+	if db.failAt != 0 && db.statements == db.failAt {
+		db.failAt = 0
+		return true
+	}
+	return false
+}
+
+func (db *testDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &failingTx{Tx: tx, db: db}, nil
+}
+
+// snapshotTables are the tables the task lifecycle and the ledger write to.
+var snapshotTables = []string{
+	"profiles",
+	"tasks",
+	"transactions",
+	"task_status_history",
+	"notifications",
+	"webhook_deliveries",
+}
+
+// snapshot returns the committed contents of snapshotTables as JSON, keyed
+// by table.
+func (db *testDB) snapshot(t *testing.T) map[string]string {
+	t.Helper()
+
+	tables := make(map[string]string, len(snapshotTables))
+	for _, table := range snapshotTables {
+		var rows string
+		err := db.pool.QueryRow(context.Background(),
+			"SELECT COALESCE(jsonb_agg(r ORDER BY r::TEXT), '[]')::TEXT FROM "+table+" r").Scan(&rows)
+		if err != nil {
+			t.Fatalf("reading %s: %v", table, err)
+		}
+		tables[table] = rows
+	}
+	return tables
+}
+
+// balance returns the user's committed credit balance.
+func (db *testDB) balance(t *testing.T, userID pgtype.UUID) int32 {
+	t.Helper()
+
+	profile, err := generated.New(db.pool).GetProfile(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+	return profile.Credits.Int32
+}
+
+// task returns the task as committed.
+func (db *testDB) task(t *testing.T, id pgtype.UUID) generated.Task {
+	t.Helper()
+
+	task, err := generated.New(db.pool).GetTask(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	return task
+}
+
+// failingTx passes statements through to Postgres, except the one testDB
+// was told to fail.
+type failingTx struct {
+	pgx.Tx
+	db *testDB
+}
+
+func (tx *failingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if tx.db.fail() {
+		return pgconn.CommandTag{}, errInjected
+	}
+	return tx.Tx.Exec(ctx, sql, args...)
+}
+
+func (tx *failingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx.db.fail() {
+		return nil, errInjected
+	}
+	return tx.Tx.Query(ctx, sql, args...)
+}
+
+func (tx *failingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx.db.fail() {
+		return failedRow{}
+	}
+	return tx.Tx.QueryRow(ctx, sql, args...)
+}
+
+type failedRow struct{}
+
+func (failedRow) Scan(dest ...any) error { return errInjected }
+
+func newID() pgtype.UUID {
+	return pgtype.UUID{Bytes: uuid.New(), Valid: true}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/egeuysall/summit/internal/events"
+	"github.com/egeuysall/summit/internal/models"
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/egeuysall/summit/internal/tasks"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// WebhookEventTypes are the events webhooks can subscribe to: every task
+// event, and ledger transactions.
+var WebhookEventTypes = webhookEventTypes()
+
+func webhookEventTypes() []string {
+	types := []string{events.LedgerTransaction}
+	for _, eventType := range taskEvents {
+		if !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	slices.Sort(types)
+	return types
+}
+
+// webhookPayload is the body of every webhook delivery.
+type webhookPayload struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// enqueueWebhooks queues an event for the webhooks owned by userIDs and for
+// webhooks that receive all events. It runs in the caller's transaction, so
+// an event is only sent if the change it describes commits.
+func enqueueWebhooks(ctx context.Context, q *generated.Queries, eventType string, data any, userIDs ...pgtype.UUID) error {
+	payload, err := json.Marshal(webhookPayload{
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.EnqueueWebhookDeliveries(ctx, generated.EnqueueWebhookDeliveriesParams{
+		EventType: eventType,
+		Payload:   payload,
+		UserIds:   userIDs,
+	})
+	return err
+}
+
+// enqueueTaskWebhooks queues the event for a task action, if it has one, for
+// the requester and claimer.
+func enqueueTaskWebhooks(ctx context.Context, q *generated.Queries, before, after generated.Task, action tasks.Action) error {
+	eventType, ok := taskEvents[action]
+	if !ok {
+		return nil
+	}
+
+	return enqueueWebhooks(ctx, q, eventType, models.ToTaskResponse(after), taskParties(before, after)...)
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/egeuysall/summit/internal/events"
+)
+
+func TestTaskEventsCanBeSubscribedTo(t *testing.T) {
+	for action, eventType := range taskEvents {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			t.Errorf("%s sends %s, which webhooks cannot subscribe to", action, eventType)
+		}
+	}
+
+	for _, eventType := range []string{
+		events.TaskReleased,
+		events.TaskRejected,
+		events.TaskExpired,
+		events.TaskDisputed,
+		events.TaskResolved,
+		events.LedgerTransaction,
+	} {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			t.Errorf("WebhookEventTypes is missing %s", eventType)
+		}
+	}
+}
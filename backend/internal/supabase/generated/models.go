@@ -8,6 +8,36 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Dispute struct {
+	ID             pgtype.UUID
+	TaskID         pgtype.UUID
+	OpenedByID     pgtype.UUID
+	Reason         string
+	Status         string
+	Outcome        pgtype.Text
+	ClaimerPercent pgtype.Int4
+	ResolutionNote pgtype.Text
+	ResolvedByID   pgtype.UUID
+	CreatedAt      pgtype.Timestamptz
+	ResolvedAt     pgtype.Timestamptz
+}
+
+type Notification struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	Type      string
+	TaskID    pgtype.UUID
+	Message   string
+	CreatedAt pgtype.Timestamptz
+	ReadAt    pgtype.Timestamptz
+	EmailedAt pgtype.Timestamptz
+}
+
+type NotificationPreference struct {
+	UserID      pgtype.UUID
+	Preferences []byte
+}
+
 type Profile struct {
 	ID        pgtype.UUID
 	Name      string
@@ -17,6 +47,25 @@ type Profile struct {
 	CreatedAt pgtype.Timestamptz
 }
 
+type Redemption struct {
+	ID            pgtype.UUID
+	UserID        pgtype.UUID
+	RewardID      int32
+	TransactionID pgtype.UUID
+	Cost          int32
+	CreatedAt     pgtype.Timestamptz
+}
+
+type Review struct {
+	ID         pgtype.UUID
+	TaskID     pgtype.UUID
+	ReviewerID pgtype.UUID
+	RevieweeID pgtype.UUID
+	Rating     int32
+	Body       string
+	CreatedAt  pgtype.Timestamptz
+}
+
 type Reward struct {
 	ID          int32
 	Name        string
@@ -25,17 +74,54 @@ type Reward struct {
 	Description pgtype.Text
 }
 
+type Skill struct {
+	Slug     string
+	Name     string
+	Category string
+}
+
+type SkillAlias struct {
+	Alias     string
+	SkillSlug string
+}
+
 type Task struct {
-	ID           pgtype.UUID
-	Title        string
-	Description  string
-	Skill        string
-	Urgency      pgtype.Text
-	CreditReward int32
-	RequesterID  pgtype.UUID
-	ClaimedByID  pgtype.UUID
-	Status       pgtype.Text
-	CreatedAt    pgtype.Timestamptz
+	ID              pgtype.UUID
+	Title           string
+	Description     string
+	Skill           string
+	Urgency         string
+	CreditReward    int32
+	RequesterID     pgtype.UUID
+	ClaimedByID     pgtype.UUID
+	Status          pgtype.Text
+	CreatedAt       pgtype.Timestamptz
+	EscrowAmount    int32
+	EscrowState     string
+	RejectionReason pgtype.Text
+	RejectionCount  int32
+	DueAt           pgtype.Timestamptz
+	UrgencyPremium  int32
+}
+
+type TaskMessage struct {
+	ID        pgtype.UUID
+	TaskID    pgtype.UUID
+	ClaimerID pgtype.UUID
+	SenderID  pgtype.UUID
+	Body      string
+	CreatedAt pgtype.Timestamptz
+	ReadAt    pgtype.Timestamptz
+}
+
+type TaskStatusHistory struct {
+	ID         pgtype.UUID
+	TaskID     pgtype.UUID
+	Action     string
+	FromStatus pgtype.Text
+	ToStatus   string
+	ActorID    pgtype.UUID
+	CreatedAt  pgtype.Timestamptz
 }
 
 type Transaction struct {
@@ -44,4 +130,47 @@ type Transaction struct {
 	TaskID    pgtype.UUID
 	Credits   int32
 	CreatedAt pgtype.Timestamptz
+	Kind      string
+	Account   string
+	EntryID   pgtype.UUID
+}
+
+type UserEmail struct {
+	UserID           pgtype.UUID
+	Email            string
+	UnsubscribeToken pgtype.UUID
+	DigestSentAt     pgtype.Timestamptz
+}
+
+type UserReputation struct {
+	UserID         pgtype.UUID
+	TasksConfirmed int32
+	RatingPoints   int32
+	Releases       int32
+	Cancellations  int32
+	Score          int32
+}
+
+type Webhook struct {
+	ID         pgtype.UUID
+	OwnerID    pgtype.UUID
+	Url        string
+	Secret     string
+	EventTypes []string
+	AllEvents  bool
+	CreatedAt  pgtype.Timestamptz
+}
+
+type WebhookDelivery struct {
+	ID             pgtype.UUID
+	WebhookID      pgtype.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	ResponseStatus pgtype.Int4
+	LastError      pgtype.Text
+	NextAttemptAt  pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
 }
@@ -72,21 +72,66 @@ func (q *Queries) DecrementCredits(ctx context.Context, arg DecrementCreditsPara
 }
 
 const getLeaderboard = `-- name: GetLeaderboard :many
-SELECT id, name, avatar_url, credits
-FROM profiles
-ORDER BY credits DESC
-LIMIT $1
+WITH scores AS (
+  SELECT p.id, p.name, p.avatar_url, r.score AS reputation,
+    (CASE $3::TEXT
+      WHEN 'earned' THEN (
+        SELECT COALESCE(SUM(tx.credits), 0) FROM transactions tx
+        LEFT JOIN tasks t ON t.id = tx.task_id
+        WHERE tx.user_id = p.id AND tx.account = 'user' AND tx.kind = 'task_payout'
+          AND ($4::TIMESTAMPTZ IS NULL OR tx.created_at >= $4::TIMESTAMPTZ)
+          AND ($5::TEXT IS NULL OR t.skill = $5::TEXT))
+      WHEN 'completed' THEN (
+        SELECT COUNT(*) FROM tasks t
+        JOIN task_status_history h ON h.task_id = t.id AND h.to_status = 'confirmed'
+        WHERE t.claimed_by_id = p.id AND t.status = 'confirmed'
+          AND ($4::TIMESTAMPTZ IS NULL OR h.created_at >= $4::TIMESTAMPTZ)
+          AND ($5::TEXT IS NULL OR t.skill = $5::TEXT))
+      ELSE COALESCE(p.credits, 0)
+    END)::INTEGER AS score
+  FROM profiles p
+  JOIN user_reputation r ON r.user_id = p.id
+),
+ranked AS (
+  SELECT id, name, avatar_url, reputation, score,
+    RANK() OVER (ORDER BY score DESC) AS rank,
+    ROW_NUMBER() OVER (ORDER BY score DESC, id) AS position
+  FROM scores
+)
+SELECT id, name, avatar_url, reputation, score, rank::INTEGER
+FROM ranked
+WHERE position <= $1::INTEGER OR id = $2::UUID
+ORDER BY position
 `
 
+type GetLeaderboardParams struct {
+	PageLimit int32
+	CallerID  pgtype.UUID
+	Metric    string
+	Since     pgtype.Timestamptz
+	Skill     pgtype.Text
+}
+
 type GetLeaderboardRow struct {
-	ID        pgtype.UUID
-	Name      string
-	AvatarUrl pgtype.Text
-	Credits   pgtype.Int4
+	ID         pgtype.UUID
+	Name       string
+	AvatarUrl  pgtype.Text
+	Reputation int32
+	Score      int32
+	Rank       int32
 }
 
-func (q *Queries) GetLeaderboard(ctx context.Context, limit int32) ([]GetLeaderboardRow, error) {
-	rows, err := q.db.Query(ctx, getLeaderboard, limit)
+// Ranks every user by the chosen metric. Returns the top page_limit users,
+// plus the caller's own row when caller_id is set and outside the top.
+// Balances only appear as the score when the metric is balance.
+func (q *Queries) GetLeaderboard(ctx context.Context, arg GetLeaderboardParams) ([]GetLeaderboardRow, error) {
+	rows, err := q.db.Query(ctx, getLeaderboard,
+		arg.PageLimit,
+		arg.CallerID,
+		arg.Metric,
+		arg.Since,
+		arg.Skill,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +143,9 @@ func (q *Queries) GetLeaderboard(ctx context.Context, limit int32) ([]GetLeaderb
 			&i.ID,
 			&i.Name,
 			&i.AvatarUrl,
-			&i.Credits,
+			&i.Reputation,
+			&i.Score,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -129,6 +176,75 @@ func (q *Queries) GetProfile(ctx context.Context, id pgtype.UUID) (Profile, erro
 	return i, err
 }
 
+const getUserReputation = `-- name: GetUserReputation :one
+SELECT user_id, tasks_confirmed, rating_points, releases, cancellations, score FROM user_reputation
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserReputation(ctx context.Context, userID pgtype.UUID) (UserReputation, error) {
+	row := q.db.QueryRow(ctx, getUserReputation, userID)
+	var i UserReputation
+	err := row.Scan(
+		&i.UserID,
+		&i.TasksConfirmed,
+		&i.RatingPoints,
+		&i.Releases,
+		&i.Cancellations,
+		&i.Score,
+	)
+	return i, err
+}
+
+const getUserStats = `-- name: GetUserStats :one
+SELECT
+  (SELECT COUNT(*) FROM tasks WHERE requester_id = $1::UUID)::INTEGER as tasks_posted,
+  (SELECT COUNT(DISTINCT task_id) FROM task_status_history
+    WHERE action = 'complete' AND actor_id = $1::UUID)::INTEGER as tasks_submitted,
+  (SELECT COUNT(*) FROM tasks
+    WHERE claimed_by_id = $1::UUID AND status = 'confirmed')::INTEGER as tasks_completed,
+  (SELECT COALESCE(AVG(EXTRACT(EPOCH FROM c.created_at - claimed.at)), 0)
+    FROM task_status_history c
+    CROSS JOIN LATERAL (
+      SELECT MAX(h.created_at) as at FROM task_status_history h
+      WHERE h.task_id = c.task_id AND h.action = 'claim'
+        AND h.actor_id = c.actor_id AND h.created_at <= c.created_at
+    ) claimed
+    WHERE c.action = 'complete' AND c.actor_id = $1::UUID)::FLOAT8 as avg_seconds_to_complete,
+  (SELECT COALESCE(SUM(credits), 0) FROM transactions
+    WHERE user_id = $1::UUID AND account = 'user' AND kind = 'task_payout')::INTEGER as credits_earned,
+  (SELECT COALESCE(array_agg(DISTINCT skill ORDER BY skill), '{}') FROM tasks
+    WHERE claimed_by_id = $1::UUID AND status = 'confirmed')::TEXT[] as skills_used,
+  (SELECT COUNT(*) FROM reviews WHERE reviewee_id = $1::UUID)::INTEGER as review_count,
+  (SELECT COALESCE(AVG(rating), 0) FROM reviews WHERE reviewee_id = $1::UUID)::FLOAT8 as average_rating
+`
+
+type GetUserStatsRow struct {
+	TasksPosted          int32
+	TasksSubmitted       int32
+	TasksCompleted       int32
+	AvgSecondsToComplete float64
+	CreditsEarned        int32
+	SkillsUsed           []string
+	ReviewCount          int32
+	AverageRating        float64
+}
+
+func (q *Queries) GetUserStats(ctx context.Context, userID pgtype.UUID) (GetUserStatsRow, error) {
+	row := q.db.QueryRow(ctx, getUserStats, userID)
+	var i GetUserStatsRow
+	err := row.Scan(
+		&i.TasksPosted,
+		&i.TasksSubmitted,
+		&i.TasksCompleted,
+		&i.AvgSecondsToComplete,
+		&i.CreditsEarned,
+		&i.SkillsUsed,
+		&i.ReviewCount,
+		&i.AverageRating,
+	)
+	return i, err
+}
+
 const incrementCredits = `-- name: IncrementCredits :exec
 UPDATE profiles
 SET credits = credits + $2
@@ -7,6 +7,8 @@ package supabase
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const getReward = `-- name: GetReward :one
@@ -61,11 +63,20 @@ func (q *Queries) GetRewardsByPlanet(ctx context.Context, planet string) ([]Rewa
 
 const listRewards = `-- name: ListRewards :many
 SELECT id, name, planet, cost, description FROM rewards
-ORDER BY cost ASC
+WHERE $1::INTEGER IS NULL
+  OR (cost, id) > ($1::INTEGER, $2::INTEGER)
+ORDER BY cost ASC, id ASC
+LIMIT $3
 `
 
-func (q *Queries) ListRewards(ctx context.Context) ([]Reward, error) {
-	rows, err := q.db.Query(ctx, listRewards)
+type ListRewardsParams struct {
+	AfterCost pgtype.Int4
+	AfterID   pgtype.Int4
+	PageLimit int32
+}
+
+func (q *Queries) ListRewards(ctx context.Context, arg ListRewardsParams) ([]Reward, error) {
+	rows, err := q.db.Query(ctx, listRewards, arg.AfterCost, arg.AfterID, arg.PageLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -11,21 +11,47 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const cancelTask = `-- name: CancelTask :exec
+const cancelTask = `-- name: CancelTask :one
 UPDATE tasks
 SET status = 'cancelled', claimed_by_id = NULL
-WHERE id = $1
+WHERE id = $1 AND status = $2
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
-func (q *Queries) CancelTask(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, cancelTask, id)
-	return err
+type CancelTaskParams struct {
+	ID     pgtype.UUID
+	Status pgtype.Text
+}
+
+func (q *Queries) CancelTask(ctx context.Context, arg CancelTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, cancelTask, arg.ID, arg.Status)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
 }
 
-const claimTask = `-- name: ClaimTask :exec
+const claimTask = `-- name: ClaimTask :one
 UPDATE tasks
 SET claimed_by_id = $2, status = 'claimed'
 WHERE id = $1 AND status = 'open'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
 type ClaimTaskParams struct {
@@ -33,46 +59,107 @@ type ClaimTaskParams struct {
 	ClaimedByID pgtype.UUID
 }
 
-func (q *Queries) ClaimTask(ctx context.Context, arg ClaimTaskParams) error {
-	_, err := q.db.Exec(ctx, claimTask, arg.ID, arg.ClaimedByID)
-	return err
+func (q *Queries) ClaimTask(ctx context.Context, arg ClaimTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, claimTask, arg.ID, arg.ClaimedByID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
 }
 
-const completeTask = `-- name: CompleteTask :exec
+const completeTask = `-- name: CompleteTask :one
 UPDATE tasks
 SET status = 'completed'
 WHERE id = $1 AND status = 'claimed'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
-func (q *Queries) CompleteTask(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, completeTask, id)
-	return err
+func (q *Queries) CompleteTask(ctx context.Context, id pgtype.UUID) (Task, error) {
+	row := q.db.QueryRow(ctx, completeTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
 }
 
-const confirmTask = `-- name: ConfirmTask :exec
+const confirmTask = `-- name: ConfirmTask :one
 UPDATE tasks
 SET status = 'confirmed'
 WHERE id = $1 AND status = 'completed'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
-func (q *Queries) ConfirmTask(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, confirmTask, id)
-	return err
+func (q *Queries) ConfirmTask(ctx context.Context, id pgtype.UUID) (Task, error) {
+	row := q.db.QueryRow(ctx, confirmTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
 }
 
 const createTask = `-- name: CreateTask :one
-INSERT INTO tasks (title, description, skill, urgency, credit_reward, requester_id)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at
+INSERT INTO tasks (title, description, skill, urgency, credit_reward, requester_id, escrow_amount, due_at, urgency_premium)
+VALUES ($1, $2, $3, $4, $5, $6, $5 + $8, $7, $8)
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
 type CreateTaskParams struct {
-	Title        string
-	Description  string
-	Skill        string
-	Urgency      pgtype.Text
-	CreditReward int32
-	RequesterID  pgtype.UUID
+	Title          string
+	Description    string
+	Skill          string
+	Urgency        string
+	CreditReward   int32
+	RequesterID    pgtype.UUID
+	DueAt          pgtype.Timestamptz
+	UrgencyPremium int32
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
@@ -83,6 +170,8 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		arg.Urgency,
 		arg.CreditReward,
 		arg.RequesterID,
+		arg.DueAt,
+		arg.UrgencyPremium,
 	)
 	var i Task
 	err := row.Scan(
@@ -96,22 +185,214 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.ClaimedByID,
 		&i.Status,
 		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
 	)
 	return i, err
 }
 
-const deleteTask = `-- name: DeleteTask :exec
+const deleteTask = `-- name: DeleteTask :one
 DELETE FROM tasks
 WHERE id = $1 AND status = 'open'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+func (q *Queries) DeleteTask(ctx context.Context, id pgtype.UUID) (Task, error) {
+	row := q.db.QueryRow(ctx, deleteTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const disputeTask = `-- name: DisputeTask :one
+UPDATE tasks
+SET status = 'disputed'
+WHERE id = $1 AND status = $2
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
 `
 
-func (q *Queries) DeleteTask(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteTask, id)
-	return err
+type DisputeTaskParams struct {
+	ID     pgtype.UUID
+	Status pgtype.Text
+}
+
+func (q *Queries) DisputeTask(ctx context.Context, arg DisputeTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, disputeTask, arg.ID, arg.Status)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const expireClaim = `-- name: ExpireClaim :one
+UPDATE tasks
+SET claimed_by_id = NULL, status = 'open', due_at = NULL
+WHERE id = $1 AND status = 'claimed' AND claimed_by_id = $2
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+type ExpireClaimParams struct {
+	ID          pgtype.UUID
+	ClaimedByID pgtype.UUID
+}
+
+func (q *Queries) ExpireClaim(ctx context.Context, arg ExpireClaimParams) (Task, error) {
+	row := q.db.QueryRow(ctx, expireClaim, arg.ID, arg.ClaimedByID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const expireTask = `-- name: ExpireTask :one
+UPDATE tasks
+SET status = 'expired'
+WHERE id = $1 AND status = 'open'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+func (q *Queries) ExpireTask(ctx context.Context, id pgtype.UUID) (Task, error) {
+	row := q.db.QueryRow(ctx, expireTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const getEscrowMismatches = `-- name: GetEscrowMismatches :many
+SELECT
+  t.id,
+  t.credit_reward,
+  t.escrow_amount,
+  t.escrow_state,
+  COALESCE(SUM(tx.credits), 0)::INTEGER as ledger_amount
+FROM tasks t
+LEFT JOIN transactions tx ON tx.task_id = t.id AND tx.account = 'escrow'
+GROUP BY t.id
+HAVING
+  t.escrow_amount <> COALESCE(SUM(tx.credits), 0)
+  OR (t.escrow_state = 'held' AND t.escrow_amount <> t.credit_reward + t.urgency_premium)
+  OR (t.escrow_state <> 'held' AND t.escrow_amount <> 0)
+`
+
+type GetEscrowMismatchesRow struct {
+	ID           pgtype.UUID
+	CreditReward int32
+	EscrowAmount int32
+	EscrowState  string
+	LedgerAmount int32
+}
+
+func (q *Queries) GetEscrowMismatches(ctx context.Context) ([]GetEscrowMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, getEscrowMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEscrowMismatchesRow
+	for rows.Next() {
+		var i GetEscrowMismatchesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreditReward,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.LedgerAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEscrowTotal = `-- name: GetEscrowTotal :one
+SELECT COALESCE(SUM(escrow_amount), 0)::INTEGER as total
+FROM tasks
+WHERE escrow_state = 'held'
+`
+
+func (q *Queries) GetEscrowTotal(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, getEscrowTotal)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
 }
 
 const getTask = `-- name: GetTask :one
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
 WHERE id = $1
 `
 
@@ -129,12 +410,18 @@ func (q *Queries) GetTask(ctx context.Context, id pgtype.UUID) (Task, error) {
 		&i.ClaimedByID,
 		&i.Status,
 		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
 	)
 	return i, err
 }
 
 const listAllTasks = `-- name: ListAllTasks :many
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
 ORDER BY created_at DESC
 LIMIT $1
 `
@@ -159,6 +446,12 @@ func (q *Queries) ListAllTasks(ctx context.Context, limit int32) ([]Task, error)
 			&i.ClaimedByID,
 			&i.Status,
 			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
 		); err != nil {
 			return nil, err
 		}
@@ -170,14 +463,21 @@ func (q *Queries) ListAllTasks(ctx context.Context, limit int32) ([]Task, error)
 	return items, nil
 }
 
-const listOpenTasks = `-- name: ListOpenTasks :many
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
-WHERE status = 'open'
-ORDER BY created_at DESC
+const listRecommendationCandidates = `-- name: ListRecommendationCandidates :many
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
+WHERE status = 'open' AND requester_id <> $1
+ORDER BY lower(skill) = ANY($2::TEXT[]) DESC, created_at DESC
+LIMIT $3
 `
 
-func (q *Queries) ListOpenTasks(ctx context.Context) ([]Task, error) {
-	rows, err := q.db.Query(ctx, listOpenTasks)
+type ListRecommendationCandidatesParams struct {
+	UserID         pgtype.UUID
+	Skills         []string
+	CandidateLimit int32
+}
+
+func (q *Queries) ListRecommendationCandidates(ctx context.Context, arg ListRecommendationCandidatesParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listRecommendationCandidates, arg.UserID, arg.Skills, arg.CandidateLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +496,12 @@ func (q *Queries) ListOpenTasks(ctx context.Context) ([]Task, error) {
 			&i.ClaimedByID,
 			&i.Status,
 			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
 		); err != nil {
 			return nil, err
 		}
@@ -208,13 +514,28 @@ func (q *Queries) ListOpenTasks(ctx context.Context) ([]Task, error) {
 }
 
 const listTasksByClaimer = `-- name: ListTasksByClaimer :many
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
 WHERE claimed_by_id = $1
-ORDER BY created_at DESC
+  AND ($2::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < ($2::TIMESTAMPTZ, $3::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListTasksByClaimer(ctx context.Context, claimedByID pgtype.UUID) ([]Task, error) {
-	rows, err := q.db.Query(ctx, listTasksByClaimer, claimedByID)
+type ListTasksByClaimerParams struct {
+	ClaimedByID    pgtype.UUID
+	AfterCreatedAt pgtype.Timestamptz
+	AfterID        pgtype.UUID
+	PageLimit      int32
+}
+
+func (q *Queries) ListTasksByClaimer(ctx context.Context, arg ListTasksByClaimerParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasksByClaimer,
+		arg.ClaimedByID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -233,6 +554,12 @@ func (q *Queries) ListTasksByClaimer(ctx context.Context, claimedByID pgtype.UUI
 			&i.ClaimedByID,
 			&i.Status,
 			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
 		); err != nil {
 			return nil, err
 		}
@@ -245,13 +572,28 @@ func (q *Queries) ListTasksByClaimer(ctx context.Context, claimedByID pgtype.UUI
 }
 
 const listTasksByRequester = `-- name: ListTasksByRequester :many
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
 WHERE requester_id = $1
-ORDER BY created_at DESC
+  AND ($2::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < ($2::TIMESTAMPTZ, $3::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) ListTasksByRequester(ctx context.Context, requesterID pgtype.UUID) ([]Task, error) {
-	rows, err := q.db.Query(ctx, listTasksByRequester, requesterID)
+type ListTasksByRequesterParams struct {
+	RequesterID    pgtype.UUID
+	AfterCreatedAt pgtype.Timestamptz
+	AfterID        pgtype.UUID
+	PageLimit      int32
+}
+
+func (q *Queries) ListTasksByRequester(ctx context.Context, arg ListTasksByRequesterParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasksByRequester,
+		arg.RequesterID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +612,12 @@ func (q *Queries) ListTasksByRequester(ctx context.Context, requesterID pgtype.U
 			&i.ClaimedByID,
 			&i.Status,
 			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
 		); err != nil {
 			return nil, err
 		}
@@ -282,7 +630,7 @@ func (q *Queries) ListTasksByRequester(ctx context.Context, requesterID pgtype.U
 }
 
 const listTasksBySkill = `-- name: ListTasksBySkill :many
-SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at FROM tasks
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
 WHERE skill = $1 AND status = 'open'
 ORDER BY credit_reward DESC
 `
@@ -307,6 +655,305 @@ func (q *Queries) ListTasksBySkill(ctx context.Context, skill string) ([]Task, e
 			&i.ClaimedByID,
 			&i.Status,
 			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksCompletedBefore = `-- name: ListTasksCompletedBefore :many
+SELECT t.id, t.title, t.description, t.skill, t.urgency, t.credit_reward, t.requester_id, t.claimed_by_id, t.status, t.created_at, t.escrow_amount, t.escrow_state, t.rejection_reason, t.rejection_count, t.due_at, t.urgency_premium FROM tasks t
+JOIN task_status_history h ON h.task_id = t.id AND h.to_status = 'completed'
+WHERE t.status = 'completed'
+GROUP BY t.id
+HAVING MAX(h.created_at) < $1::TIMESTAMPTZ
+`
+
+func (q *Queries) ListTasksCompletedBefore(ctx context.Context, completedBefore pgtype.Timestamptz) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasksCompletedBefore, completedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Skill,
+			&i.Urgency,
+			&i.CreditReward,
+			&i.RequesterID,
+			&i.ClaimedByID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksDueBefore = `-- name: ListTasksDueBefore :many
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
+WHERE status IN ('open', 'claimed') AND due_at < $1::TIMESTAMPTZ
+ORDER BY due_at
+`
+
+func (q *Queries) ListTasksDueBefore(ctx context.Context, dueBefore pgtype.Timestamptz) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasksDueBefore, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Skill,
+			&i.Urgency,
+			&i.CreditReward,
+			&i.RequesterID,
+			&i.ClaimedByID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rejectTask = `-- name: RejectTask :one
+UPDATE tasks
+SET
+  status = CASE WHEN rejection_count + 1 >= $1::INTEGER THEN 'disputed' ELSE 'claimed' END,
+  rejection_reason = $2,
+  rejection_count = rejection_count + 1
+WHERE id = $3 AND status = 'completed'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+type RejectTaskParams struct {
+	MaxRejections   int32
+	RejectionReason pgtype.Text
+	ID              pgtype.UUID
+}
+
+func (q *Queries) RejectTask(ctx context.Context, arg RejectTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, rejectTask, arg.MaxRejections, arg.RejectionReason, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const releaseTask = `-- name: ReleaseTask :one
+UPDATE tasks
+SET claimed_by_id = NULL, status = 'open'
+WHERE id = $1 AND status = 'claimed' AND claimed_by_id = $2
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+type ReleaseTaskParams struct {
+	ID          pgtype.UUID
+	ClaimedByID pgtype.UUID
+}
+
+func (q *Queries) ReleaseTask(ctx context.Context, arg ReleaseTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, releaseTask, arg.ID, arg.ClaimedByID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const resolveTask = `-- name: ResolveTask :one
+UPDATE tasks
+SET status = $2
+WHERE id = $1 AND status = 'disputed'
+RETURNING id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium
+`
+
+type ResolveTaskParams struct {
+	ID     pgtype.UUID
+	Status pgtype.Text
+}
+
+func (q *Queries) ResolveTask(ctx context.Context, arg ResolveTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, resolveTask, arg.ID, arg.Status)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Skill,
+		&i.Urgency,
+		&i.CreditReward,
+		&i.RequesterID,
+		&i.ClaimedByID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.EscrowAmount,
+		&i.EscrowState,
+		&i.RejectionReason,
+		&i.RejectionCount,
+		&i.DueAt,
+		&i.UrgencyPremium,
+	)
+	return i, err
+}
+
+const searchTasks = `-- name: SearchTasks :many
+SELECT id, title, description, skill, urgency, credit_reward, requester_id, claimed_by_id, status, created_at, escrow_amount, escrow_state, rejection_reason, rejection_count, due_at, urgency_premium FROM tasks
+WHERE status = $1::TEXT
+  AND ($2::TEXT IS NULL
+    OR to_tsvector('english', title || ' ' || description) @@ websearch_to_tsquery('english', $2::TEXT))
+  AND ($3::TEXT IS NULL OR skill = $3::TEXT)
+  AND ($4::TEXT IS NULL OR urgency = $4::TEXT)
+  AND ($5::INTEGER IS NULL OR credit_reward >= $5::INTEGER)
+  AND ($6::INTEGER IS NULL OR credit_reward <= $6::INTEGER)
+  AND ($7::UUID IS NULL OR requester_id = $7::UUID)
+  AND ($8::TIMESTAMPTZ IS NULL
+    OR (CASE $9::TEXT
+    WHEN 'reward' THEN credit_reward
+    WHEN 'urgency' THEN CASE urgency WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END
+    ELSE 0
+  END, created_at, id)
+      < ($10::INTEGER, $8::TIMESTAMPTZ, $11::UUID))
+ORDER BY
+  CASE $9::TEXT
+    WHEN 'reward' THEN credit_reward
+    WHEN 'urgency' THEN CASE urgency WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END
+    ELSE 0
+  END DESC,
+  created_at DESC,
+  id DESC
+LIMIT $12
+`
+
+type SearchTasksParams struct {
+	Status         string
+	Query          pgtype.Text
+	Skill          pgtype.Text
+	Urgency        pgtype.Text
+	MinReward      pgtype.Int4
+	MaxReward      pgtype.Int4
+	RequesterID    pgtype.UUID
+	AfterCreatedAt pgtype.Timestamptz
+	Sort           string
+	AfterKey       pgtype.Int4
+	AfterID        pgtype.UUID
+	PageLimit      int32
+}
+
+func (q *Queries) SearchTasks(ctx context.Context, arg SearchTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, searchTasks,
+		arg.Status,
+		arg.Query,
+		arg.Skill,
+		arg.Urgency,
+		arg.MinReward,
+		arg.MaxReward,
+		arg.RequesterID,
+		arg.AfterCreatedAt,
+		arg.Sort,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Skill,
+			&i.Urgency,
+			&i.CreditReward,
+			&i.RequesterID,
+			&i.ClaimedByID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.EscrowAmount,
+			&i.EscrowState,
+			&i.RejectionReason,
+			&i.RejectionCount,
+			&i.DueAt,
+			&i.UrgencyPremium,
 		); err != nil {
 			return nil, err
 		}
@@ -317,3 +964,23 @@ func (q *Queries) ListTasksBySkill(ctx context.Context, skill string) ([]Task, e
 	}
 	return items, nil
 }
+
+const settleEscrow = `-- name: SettleEscrow :one
+UPDATE tasks t
+SET escrow_amount = 0, escrow_state = $2
+FROM (SELECT l.id, l.escrow_amount FROM tasks l WHERE l.id = $1 FOR UPDATE) held
+WHERE t.id = held.id AND t.escrow_state = 'held'
+RETURNING held.escrow_amount
+`
+
+type SettleEscrowParams struct {
+	ID          pgtype.UUID
+	EscrowState string
+}
+
+func (q *Queries) SettleEscrow(ctx context.Context, arg SettleEscrowParams) (int32, error) {
+	row := q.db.QueryRow(ctx, settleEscrow, arg.ID, arg.EscrowState)
+	var escrow_amount int32
+	err := row.Scan(&escrow_amount)
+	return escrow_amount, err
+}
@@ -12,19 +12,29 @@ import (
 )
 
 const createTransaction = `-- name: CreateTransaction :one
-INSERT INTO transactions (user_id, task_id, credits)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, task_id, credits, created_at
+INSERT INTO transactions (user_id, task_id, credits, kind, account, entry_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, task_id, credits, created_at, kind, account, entry_id
 `
 
 type CreateTransactionParams struct {
 	UserID  pgtype.UUID
 	TaskID  pgtype.UUID
 	Credits int32
+	Kind    string
+	Account string
+	EntryID pgtype.UUID
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
-	row := q.db.QueryRow(ctx, createTransaction, arg.UserID, arg.TaskID, arg.Credits)
+	row := q.db.QueryRow(ctx, createTransaction,
+		arg.UserID,
+		arg.TaskID,
+		arg.Credits,
+		arg.Kind,
+		arg.Account,
+		arg.EntryID,
+	)
 	var i Transaction
 	err := row.Scan(
 		&i.ID,
@@ -32,13 +42,16 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.TaskID,
 		&i.Credits,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Account,
+		&i.EntryID,
 	)
 	return i, err
 }
 
 const getAllTransactions = `-- name: GetAllTransactions :many
 SELECT
-  t.id, t.user_id, t.task_id, t.credits, t.created_at,
+  t.id, t.user_id, t.task_id, t.credits, t.created_at, t.kind, t.account, t.entry_id,
   p.name as user_name
 FROM transactions t
 JOIN profiles p ON t.user_id = p.id
@@ -52,6 +65,9 @@ type GetAllTransactionsRow struct {
 	TaskID    pgtype.UUID
 	Credits   int32
 	CreatedAt pgtype.Timestamptz
+	Kind      string
+	Account   string
+	EntryID   pgtype.UUID
 	UserName  string
 }
 
@@ -70,6 +86,9 @@ func (q *Queries) GetAllTransactions(ctx context.Context, limit int32) ([]GetAll
 			&i.TaskID,
 			&i.Credits,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.Account,
+			&i.EntryID,
 			&i.UserName,
 		); err != nil {
 			return nil, err
@@ -82,8 +101,45 @@ func (q *Queries) GetAllTransactions(ctx context.Context, limit int32) ([]GetAll
 	return items, nil
 }
 
+const getBalanceMismatches = `-- name: GetBalanceMismatches :many
+SELECT
+  p.id,
+  p.credits,
+  COALESCE(SUM(t.credits), 0)::INTEGER as ledger_credits
+FROM profiles p
+LEFT JOIN transactions t ON t.user_id = p.id AND t.account = 'user'
+GROUP BY p.id
+HAVING p.credits IS DISTINCT FROM COALESCE(SUM(t.credits), 0)::INTEGER
+`
+
+type GetBalanceMismatchesRow struct {
+	ID            pgtype.UUID
+	Credits       pgtype.Int4
+	LedgerCredits int32
+}
+
+func (q *Queries) GetBalanceMismatches(ctx context.Context) ([]GetBalanceMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, getBalanceMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBalanceMismatchesRow
+	for rows.Next() {
+		var i GetBalanceMismatchesRow
+		if err := rows.Scan(&i.ID, &i.Credits, &i.LedgerCredits); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTaskTransactions = `-- name: GetTaskTransactions :many
-SELECT id, user_id, task_id, credits, created_at FROM transactions
+SELECT id, user_id, task_id, credits, created_at, kind, account, entry_id FROM transactions
 WHERE task_id = $1
 ORDER BY created_at DESC
 `
@@ -103,6 +159,9 @@ func (q *Queries) GetTaskTransactions(ctx context.Context, taskID pgtype.UUID) (
 			&i.TaskID,
 			&i.Credits,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.Account,
+			&i.EntryID,
 		); err != nil {
 			return nil, err
 		}
@@ -114,20 +173,61 @@ func (q *Queries) GetTaskTransactions(ctx context.Context, taskID pgtype.UUID) (
 	return items, nil
 }
 
+const getUnbalancedEntries = `-- name: GetUnbalancedEntries :many
+SELECT entry_id, SUM(credits)::INTEGER as total
+FROM transactions
+GROUP BY entry_id
+HAVING SUM(credits) <> 0
+`
+
+type GetUnbalancedEntriesRow struct {
+	EntryID pgtype.UUID
+	Total   int32
+}
+
+func (q *Queries) GetUnbalancedEntries(ctx context.Context) ([]GetUnbalancedEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getUnbalancedEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnbalancedEntriesRow
+	for rows.Next() {
+		var i GetUnbalancedEntriesRow
+		if err := rows.Scan(&i.EntryID, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserTransactions = `-- name: GetUserTransactions :many
-SELECT id, user_id, task_id, credits, created_at FROM transactions
+SELECT id, user_id, task_id, credits, created_at, kind, account, entry_id FROM transactions
 WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2
+  AND ($2::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < ($2::TIMESTAMPTZ, $3::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
 `
 
 type GetUserTransactionsParams struct {
-	UserID pgtype.UUID
-	Limit  int32
+	UserID         pgtype.UUID
+	AfterCreatedAt pgtype.Timestamptz
+	AfterID        pgtype.UUID
+	PageLimit      int32
 }
 
 func (q *Queries) GetUserTransactions(ctx context.Context, arg GetUserTransactionsParams) ([]Transaction, error) {
-	rows, err := q.db.Query(ctx, getUserTransactions, arg.UserID, arg.Limit)
+	rows, err := q.db.Query(ctx, getUserTransactions,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +241,9 @@ func (q *Queries) GetUserTransactions(ctx context.Context, arg GetUserTransactio
 			&i.TaskID,
 			&i.Credits,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.Account,
+			&i.EntryID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,6 @@
+-- Keep ledger rows when an open task is deleted; the refund is recorded
+-- before the task row goes away.
+ALTER TABLE transactions
+  DROP CONSTRAINT transactions_task_id_fkey,
+  ADD CONSTRAINT transactions_task_id_fkey
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE SET NULL;
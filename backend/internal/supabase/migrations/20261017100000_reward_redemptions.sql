@@ -0,0 +1,31 @@
+-- Record what each ledger entry was for instead of guessing from its sign.
+ALTER TABLE transactions ADD COLUMN kind TEXT;
+
+UPDATE transactions t
+SET kind = CASE
+  WHEN t.credits < 0 THEN 'task_escrow'
+  WHEN EXISTS (
+    SELECT 1 FROM tasks tk
+    WHERE tk.id = t.task_id AND tk.claimed_by_id = t.user_id
+  ) THEN 'task_payout'
+  ELSE 'task_refund'
+END;
+
+ALTER TABLE transactions ALTER COLUMN kind SET NOT NULL;
+
+CREATE TABLE redemptions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES profiles(id),
+  reward_id INTEGER NOT NULL REFERENCES rewards(id),
+  transaction_id UUID NOT NULL REFERENCES transactions(id),
+  cost INTEGER NOT NULL CHECK (cost > 0),
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_redemptions_user ON redemptions(user_id, created_at DESC);
+
+ALTER TABLE redemptions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can view their own redemptions"
+  ON redemptions FOR SELECT
+  USING (auth.uid() = user_id);
@@ -0,0 +1,60 @@
+-- Turn transactions into a double-entry ledger. Every entry is a pair of rows
+-- sharing entry_id: one against a user's account and the opposite amount
+-- against the escrow account (task credits) or the system account.
+ALTER TABLE transactions
+  ADD COLUMN account TEXT NOT NULL DEFAULT 'user',
+  ADD COLUMN entry_id UUID;
+
+ALTER TABLE transactions ALTER COLUMN user_id DROP NOT NULL;
+
+UPDATE transactions SET entry_id = id;
+
+INSERT INTO transactions (user_id, task_id, credits, created_at, kind, account, entry_id)
+SELECT
+  NULL,
+  task_id,
+  -credits,
+  created_at,
+  kind,
+  CASE WHEN kind = 'redemption' THEN 'system' ELSE 'escrow' END,
+  entry_id
+FROM transactions;
+
+-- Every existing profile started with the signup bonus; anything else the
+-- ledger cannot explain is recorded as an adjustment.
+WITH bonus AS (
+  SELECT id AS user_id, gen_random_uuid() AS entry_id, created_at
+  FROM profiles
+)
+INSERT INTO transactions (user_id, task_id, credits, created_at, kind, account, entry_id)
+SELECT user_id, NULL, 100, created_at, 'signup_bonus', 'user', entry_id FROM bonus
+UNION ALL
+SELECT NULL, NULL, -100, created_at, 'signup_bonus', 'system', entry_id FROM bonus;
+
+WITH adjustment AS (
+  SELECT
+    p.id AS user_id,
+    p.credits - COALESCE(SUM(t.credits), 0) AS credits,
+    gen_random_uuid() AS entry_id
+  FROM profiles p
+  LEFT JOIN transactions t ON t.user_id = p.id AND t.account = 'user'
+  GROUP BY p.id
+  HAVING p.credits <> COALESCE(SUM(t.credits), 0)
+)
+INSERT INTO transactions (user_id, task_id, credits, kind, account, entry_id)
+SELECT user_id, NULL, credits, 'admin_adjustment', 'user', entry_id FROM adjustment
+UNION ALL
+SELECT NULL, NULL, -credits, 'admin_adjustment', 'system', entry_id FROM adjustment;
+
+ALTER TABLE transactions
+  ALTER COLUMN account DROP DEFAULT,
+  ALTER COLUMN entry_id SET NOT NULL,
+  ADD CONSTRAINT transactions_kind_check CHECK (kind IN (
+    'task_escrow', 'task_refund', 'task_payout',
+    'signup_bonus', 'redemption', 'admin_adjustment'
+  )),
+  ADD CONSTRAINT transactions_account_check CHECK (account IN ('user', 'escrow', 'system')),
+  ADD CONSTRAINT transactions_user_account_check CHECK ((account = 'user') = (user_id IS NOT NULL));
+
+CREATE INDEX idx_transactions_user ON transactions(user_id, created_at DESC);
+CREATE INDEX idx_transactions_entry ON transactions(entry_id);
@@ -0,0 +1,16 @@
+-- Track the credits each task holds in escrow between posting and payout.
+ALTER TABLE tasks
+  ADD COLUMN escrow_amount INTEGER NOT NULL DEFAULT 0 CHECK (escrow_amount >= 0),
+  ADD COLUMN escrow_state TEXT NOT NULL DEFAULT 'held'
+    CHECK (escrow_state IN ('held', 'released', 'refunded'));
+
+UPDATE tasks
+SET
+  escrow_amount = CASE WHEN status IN ('confirmed', 'cancelled') THEN 0 ELSE credit_reward END,
+  escrow_state = CASE status
+    WHEN 'confirmed' THEN 'released'
+    WHEN 'cancelled' THEN 'refunded'
+    ELSE 'held'
+  END;
+
+CREATE INDEX idx_tasks_escrow_state ON tasks(escrow_state);
@@ -0,0 +1,38 @@
+-- Record every task status transition with the actor that made it.
+CREATE TABLE task_status_history (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  action TEXT NOT NULL,
+  from_status TEXT,
+  to_status TEXT NOT NULL,
+  actor_id UUID REFERENCES profiles(id),
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_status_history_task ON task_status_history(task_id, created_at);
+
+-- Earlier transitions were not recorded; keep the creation and, for tasks
+-- that have moved on, their current status without an actor.
+INSERT INTO task_status_history (task_id, action, from_status, to_status, actor_id, created_at)
+SELECT id, 'create', NULL, 'open', requester_id, created_at FROM tasks;
+
+INSERT INTO task_status_history (task_id, action, from_status, to_status, actor_id)
+SELECT
+  id,
+  CASE status
+    WHEN 'claimed' THEN 'claim'
+    WHEN 'completed' THEN 'complete'
+    WHEN 'confirmed' THEN 'confirm'
+    ELSE 'cancel'
+  END,
+  NULL,
+  status,
+  NULL
+FROM tasks
+WHERE status <> 'open';
+
+ALTER TABLE task_status_history ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Anyone can view task status history"
+  ON task_status_history FOR SELECT
+  USING (true);
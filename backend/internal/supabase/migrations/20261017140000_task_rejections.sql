@@ -0,0 +1,4 @@
+-- Let requesters send completed work back with a reason.
+ALTER TABLE tasks
+  ADD COLUMN rejection_reason TEXT,
+  ADD COLUMN rejection_count INTEGER NOT NULL DEFAULT 0;
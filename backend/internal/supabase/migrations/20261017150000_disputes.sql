@@ -0,0 +1,37 @@
+-- Disputes freeze a task's escrow until an admin awards, refunds or splits it.
+CREATE TABLE disputes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id),
+  opened_by_id UUID NOT NULL REFERENCES profiles(id),
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+  outcome TEXT CHECK (outcome IN ('award', 'refund', 'split')),
+  claimer_percent INTEGER CHECK (claimer_percent BETWEEN 0 AND 100),
+  resolution_note TEXT,
+  resolved_by_id UUID REFERENCES profiles(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_disputes_open_task ON disputes(task_id) WHERE status = 'open';
+
+-- Tasks already escalated by repeated rejections get a dispute to resolve.
+INSERT INTO disputes (task_id, opened_by_id, reason)
+SELECT id, requester_id, COALESCE(rejection_reason, 'Rejected too many times')
+FROM tasks
+WHERE status = 'disputed';
+
+ALTER TABLE tasks
+  DROP CONSTRAINT tasks_escrow_state_check,
+  ADD CONSTRAINT tasks_escrow_state_check
+    CHECK (escrow_state IN ('held', 'released', 'refunded', 'split'));
+
+ALTER TABLE disputes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Task parties can view disputes"
+  ON disputes FOR SELECT
+  USING (EXISTS (
+    SELECT 1 FROM tasks
+    WHERE tasks.id = disputes.task_id
+      AND (auth.uid() = tasks.requester_id OR auth.uid() = tasks.claimed_by_id)
+  ));
@@ -0,0 +1,5 @@
+-- Optional deadline. Open tasks past it expire with a refund; claimed tasks
+-- past it go back to the open pool.
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMPTZ;
+
+CREATE INDEX idx_tasks_due_at ON tasks(due_at) WHERE status IN ('open', 'claimed');
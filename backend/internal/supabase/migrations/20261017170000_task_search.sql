@@ -0,0 +1,9 @@
+-- Full-text search over title and description, matching the expression
+-- used by SearchTasks so the planner can use the index.
+CREATE INDEX idx_tasks_search ON tasks
+  USING GIN (to_tsvector('english', title || ' ' || description));
+
+-- Browse filters always pin a status, then sort or filter by one column.
+CREATE INDEX idx_tasks_status_created ON tasks(status, created_at DESC);
+CREATE INDEX idx_tasks_status_reward ON tasks(status, credit_reward DESC);
+CREATE INDEX idx_tasks_status_skill ON tasks(status, skill);
@@ -0,0 +1,103 @@
+-- Canonical skills. Tasks and profiles store slugs; aliases map the other
+-- spellings users type (stored lowercase) onto them.
+CREATE TABLE skills (
+  slug TEXT PRIMARY KEY CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+  name TEXT NOT NULL,
+  category TEXT NOT NULL DEFAULT 'other'
+);
+
+CREATE TABLE skill_aliases (
+  alias TEXT PRIMARY KEY CHECK (alias = lower(alias)),
+  skill_slug TEXT NOT NULL REFERENCES skills(slug) ON DELETE CASCADE
+);
+
+INSERT INTO skills (slug, name, category) VALUES
+  ('go', 'Go', 'development'),
+  ('javascript', 'JavaScript', 'development'),
+  ('typescript', 'TypeScript', 'development'),
+  ('python', 'Python', 'development'),
+  ('rust', 'Rust', 'development'),
+  ('java', 'Java', 'development'),
+  ('react', 'React', 'development'),
+  ('sql', 'SQL', 'development'),
+  ('devops', 'DevOps', 'development'),
+  ('ui-design', 'UI Design', 'design'),
+  ('graphic-design', 'Graphic Design', 'design'),
+  ('copywriting', 'Copywriting', 'writing'),
+  ('editing', 'Editing', 'writing'),
+  ('translation', 'Translation', 'writing'),
+  ('data-analysis', 'Data Analysis', 'data'),
+  ('machine-learning', 'Machine Learning', 'data'),
+  ('seo', 'SEO', 'marketing'),
+  ('social-media', 'Social Media', 'marketing'),
+  ('other', 'Other', 'other');
+
+INSERT INTO skill_aliases (alias, skill_slug) VALUES
+  ('golang', 'go'),
+  ('js', 'javascript'),
+  ('node', 'javascript'),
+  ('nodejs', 'javascript'),
+  ('ts', 'typescript'),
+  ('py', 'python'),
+  ('reactjs', 'react'),
+  ('react.js', 'react'),
+  ('postgres', 'sql'),
+  ('postgresql', 'sql'),
+  ('ui', 'ui-design'),
+  ('ux', 'ui-design'),
+  ('ux design', 'ui-design'),
+  ('design', 'graphic-design'),
+  ('writing', 'copywriting'),
+  ('proofreading', 'editing'),
+  ('ml', 'machine-learning'),
+  ('ai', 'machine-learning'),
+  ('analytics', 'data-analysis');
+
+CREATE INDEX idx_skill_aliases_skill ON skill_aliases(skill_slug);
+
+-- Maps free-text skills to a canonical slug by slug, name or alias.
+CREATE FUNCTION pg_temp.canonical_skill(raw TEXT) RETURNS TEXT AS $$
+  SELECT s.slug FROM skills s
+  WHERE s.slug = trim(both '-' FROM regexp_replace(lower(trim(raw)), '[^a-z0-9]+', '-', 'g'))
+     OR lower(s.name) = lower(trim(raw))
+     OR s.slug IN (SELECT skill_slug FROM skill_aliases WHERE alias = lower(trim(raw)))
+  LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+-- Skills already in use that match nothing above become their own
+-- canonical entries, so no task or profile loses its skill.
+INSERT INTO skills (slug, name, category)
+SELECT DISTINCT ON (slug) slug, trim(raw), 'other'
+FROM (
+  SELECT raw, trim(both '-' FROM regexp_replace(lower(trim(raw)), '[^a-z0-9]+', '-', 'g')) AS slug
+  FROM (SELECT skill AS raw FROM tasks UNION SELECT unnest(skills) FROM profiles) used
+  WHERE pg_temp.canonical_skill(raw) IS NULL
+) unknown
+WHERE slug <> ''
+ORDER BY slug, raw;
+
+UPDATE tasks SET skill = COALESCE(pg_temp.canonical_skill(skill), 'other');
+
+UPDATE profiles SET skills = ARRAY(
+  SELECT canonical FROM (
+    SELECT pg_temp.canonical_skill(s) AS canonical, MIN(ord) AS ord
+    FROM unnest(skills) WITH ORDINALITY AS u(s, ord)
+    GROUP BY 1
+  ) mapped
+  WHERE canonical IS NOT NULL
+  ORDER BY ord
+);
+
+ALTER TABLE tasks
+  ADD CONSTRAINT tasks_skill_fkey FOREIGN KEY (skill) REFERENCES skills(slug);
+
+ALTER TABLE skills ENABLE ROW LEVEL SECURITY;
+ALTER TABLE skill_aliases ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Anyone can view skills"
+  ON skills FOR SELECT
+  USING (true);
+
+CREATE POLICY "Anyone can view skill aliases"
+  ON skill_aliases FOR SELECT
+  USING (true);
@@ -0,0 +1,17 @@
+-- Urgency was free text. Map what we can onto the fixed levels and treat
+-- anything else as medium, the old default.
+UPDATE tasks SET urgency = CASE lower(trim(urgency))
+  WHEN 'low' THEN 'low'
+  WHEN 'high' THEN 'high'
+  WHEN 'critical' THEN 'critical'
+  WHEN 'urgent' THEN 'critical'
+  ELSE 'medium'
+END;
+
+-- urgency_premium is escrowed with the reward and paid out with it, so
+-- held escrow is now credit_reward + urgency_premium.
+ALTER TABLE tasks
+  ALTER COLUMN urgency SET NOT NULL,
+  ADD CONSTRAINT tasks_urgency_check
+    CHECK (urgency IN ('low', 'medium', 'high', 'critical')),
+  ADD COLUMN urgency_premium INTEGER NOT NULL DEFAULT 0 CHECK (urgency_premium >= 0);
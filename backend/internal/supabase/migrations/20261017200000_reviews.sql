@@ -0,0 +1,19 @@
+-- Each party to a confirmed task may review the other once.
+CREATE TABLE reviews (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  reviewer_id UUID NOT NULL REFERENCES profiles(id),
+  reviewee_id UUID NOT NULL REFERENCES profiles(id),
+  rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (task_id, reviewer_id)
+);
+
+CREATE INDEX idx_reviews_reviewee ON reviews(reviewee_id, created_at DESC);
+
+ALTER TABLE reviews ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Anyone can view reviews"
+  ON reviews FOR SELECT
+  USING (true);
@@ -0,0 +1,24 @@
+-- Reputation inputs and score per user. Confirmed work and good ratings
+-- earn points; walking away from claimed work or cancelling someone's
+-- in-progress work costs them. Trust levels are derived from score in Go.
+CREATE VIEW user_reputation AS
+SELECT
+  p.id AS user_id,
+  c.tasks_confirmed,
+  c.rating_points,
+  c.releases,
+  c.cancellations,
+  GREATEST(10 * c.tasks_confirmed + 5 * c.rating_points - 5 * c.releases - 8 * c.cancellations, 0)::INTEGER AS score
+FROM profiles p
+CROSS JOIN LATERAL (
+  SELECT
+    (SELECT COUNT(*) FROM tasks t
+      WHERE t.claimed_by_id = p.id AND t.status = 'confirmed')::INTEGER AS tasks_confirmed,
+    (SELECT COALESCE(SUM(r.rating - 3), 0) FROM reviews r
+      WHERE r.reviewee_id = p.id)::INTEGER AS rating_points,
+    (SELECT COUNT(*) FROM task_status_history h
+      WHERE h.actor_id = p.id AND h.action = 'release')::INTEGER AS releases,
+    (SELECT COUNT(*) FROM task_status_history h
+      WHERE h.actor_id = p.id AND h.action = 'cancel'
+        AND h.from_status IN ('claimed', 'completed'))::INTEGER AS cancellations
+) c;
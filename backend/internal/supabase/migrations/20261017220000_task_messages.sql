@@ -0,0 +1,23 @@
+-- Conversation between a task's requester and its claimer. Each claim gets
+-- its own thread, keyed by claimer_id, so a later claimer does not see an
+-- earlier claimer's messages. read_at is set once the other party reads it.
+CREATE TABLE task_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  claimer_id UUID NOT NULL REFERENCES profiles(id),
+  sender_id UUID NOT NULL REFERENCES profiles(id),
+  body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 2000),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  read_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_task_messages_thread ON task_messages(task_id, claimer_id, created_at DESC);
+
+ALTER TABLE task_messages ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Task parties can view their messages"
+  ON task_messages FOR SELECT
+  USING (auth.uid() = claimer_id OR EXISTS (
+    SELECT 1 FROM tasks
+    WHERE tasks.id = task_messages.task_id AND auth.uid() = tasks.requester_id
+  ));
@@ -0,0 +1,47 @@
+-- Email addresses for notification emails and digests. Kept out of
+-- profiles, which anyone can read, and copied from auth.users so queries
+-- don't need the auth schema.
+CREATE TABLE user_emails (
+  user_id UUID PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  unsubscribe_token UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  digest_sent_at TIMESTAMPTZ
+);
+
+-- Set once the email job has looked at a notification, whether or not it
+-- sent anything. Existing notifications are treated as already handled.
+ALTER TABLE notifications ADD COLUMN emailed_at TIMESTAMPTZ;
+UPDATE notifications SET emailed_at = created_at;
+
+CREATE INDEX idx_notifications_unemailed ON notifications(created_at) WHERE emailed_at IS NULL;
+
+ALTER TABLE user_emails ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can view their own email settings"
+  ON user_emails FOR SELECT
+  USING (auth.uid() = user_id);
+
+CREATE FUNCTION sync_user_email() RETURNS TRIGGER
+LANGUAGE plpgsql SECURITY DEFINER SET search_path = public AS $$
+BEGIN
+  INSERT INTO user_emails (user_id, email)
+  SELECT p.id, u.email FROM profiles p
+  JOIN auth.users u ON u.id = p.id
+  WHERE p.id = NEW.id AND u.email IS NOT NULL
+  ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email;
+  RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER sync_email_on_profile_insert
+  AFTER INSERT ON profiles
+  FOR EACH ROW EXECUTE FUNCTION sync_user_email();
+
+CREATE TRIGGER sync_email_on_auth_update
+  AFTER UPDATE OF email ON auth.users
+  FOR EACH ROW EXECUTE FUNCTION sync_user_email();
+
+INSERT INTO user_emails (user_id, email)
+SELECT p.id, u.email FROM profiles p
+JOIN auth.users u ON u.id = p.id
+WHERE u.email IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Notification preferences move out of profiles, which anyone can read, into
+-- a table only their owner can see. Users without a row have every type on.
+CREATE TABLE notification_preferences (
+  user_id UUID PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+  preferences JSONB NOT NULL DEFAULT '{}'
+);
+
+INSERT INTO notification_preferences (user_id, preferences)
+SELECT id, notification_preferences FROM profiles
+WHERE notification_preferences <> '{}';
+
+ALTER TABLE profiles DROP COLUMN notification_preferences;
+
+ALTER TABLE notification_preferences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can view their own notification preferences"
+  ON notification_preferences FOR SELECT
+  USING (auth.uid() = user_id);
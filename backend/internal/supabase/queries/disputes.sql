@@ -0,0 +1,37 @@
+-- name: CreateDispute :one
+INSERT INTO disputes (task_id, opened_by_id, reason)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetDispute :one
+SELECT * FROM disputes
+WHERE id = $1;
+
+-- name: ListOpenDisputes :many
+SELECT * FROM disputes
+WHERE status = 'open'
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) > (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at ASC, id ASC
+LIMIT @page_limit;
+
+-- name: ListUserDisputes :many
+SELECT d.* FROM disputes d
+JOIN tasks t ON d.task_id = t.id
+WHERE (t.requester_id = @user_id OR t.claimed_by_id = @user_id)
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (d.created_at, d.id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT @page_limit;
+
+-- name: ResolveDispute :one
+UPDATE disputes
+SET
+  status = 'resolved',
+  outcome = $2,
+  claimer_percent = $3,
+  resolution_note = $4,
+  resolved_by_id = $5,
+  resolved_at = NOW()
+WHERE id = $1 AND status = 'open'
+RETURNING *;
@@ -0,0 +1,72 @@
+-- name: ClaimNotificationEmails :many
+-- Marks the oldest unhandled notifications as emailed and returns the ones
+-- to send: those of the given types, for users with an address who have not
+-- turned the email off. The rest are marked and skipped.
+WITH due AS (
+  SELECT id FROM notifications
+  WHERE emailed_at IS NULL
+  ORDER BY created_at
+  LIMIT @batch_size
+  FOR UPDATE SKIP LOCKED
+),
+marked AS (
+  UPDATE notifications n
+  SET emailed_at = NOW()
+  FROM due
+  WHERE n.id = due.id
+  RETURNING n.id, n.user_id, n.type, n.task_id, n.created_at
+)
+SELECT m.id, m.type, m.task_id, p.name, e.email, e.unsubscribe_token,
+  t.title, t.credit_reward, c.name as claimer_name
+FROM marked m
+JOIN profiles p ON p.id = m.user_id
+JOIN user_emails e ON e.user_id = m.user_id
+JOIN tasks t ON t.id = m.task_id
+LEFT JOIN profiles c ON c.id = t.claimed_by_id
+LEFT JOIN notification_preferences np ON np.user_id = m.user_id
+WHERE m.type = ANY(@types::TEXT[])
+  AND COALESCE((np.preferences ->> ('email_' || m.type))::BOOLEAN, TRUE)
+ORDER BY m.created_at;
+
+-- name: ClaimDigestRecipients :many
+-- Marks a batch of users whose last digest went out before due_before as
+-- sent one now. since is when their previous digest went out, or a day ago
+-- for their first.
+WITH due AS (
+  SELECT e.user_id, e.digest_sent_at
+  FROM user_emails e
+  JOIN profiles p ON p.id = e.user_id
+  LEFT JOIN notification_preferences np ON np.user_id = e.user_id
+  WHERE (e.digest_sent_at IS NULL OR e.digest_sent_at < @due_before)
+    AND cardinality(p.skills) > 0
+    AND COALESCE((np.preferences ->> 'email_digest')::BOOLEAN, TRUE)
+  ORDER BY e.digest_sent_at NULLS FIRST
+  LIMIT @batch_size
+  FOR UPDATE OF e SKIP LOCKED
+)
+UPDATE user_emails e
+SET digest_sent_at = NOW()
+FROM due, profiles p
+WHERE e.user_id = due.user_id AND p.id = e.user_id
+RETURNING e.user_id, e.email, e.unsubscribe_token, p.name, p.skills,
+  COALESCE(due.digest_sent_at, NOW() - INTERVAL '1 day')::TIMESTAMPTZ as since;
+
+-- name: ListDigestTasks :many
+-- Open tasks in the given skills posted since the user's last digest,
+-- excluding their own.
+SELECT * FROM tasks
+WHERE status = 'open'
+  AND skill = ANY(@skills::TEXT[])
+  AND created_at > @since
+  AND requester_id <> @user_id
+ORDER BY created_at DESC
+LIMIT @page_limit;
+
+-- name: UnsubscribeEmail :one
+-- Merges preferences into those of the user holding the token.
+INSERT INTO notification_preferences (user_id, preferences)
+SELECT e.user_id, @preferences::JSONB FROM user_emails e
+WHERE e.unsubscribe_token = @token
+ON CONFLICT (user_id) DO UPDATE
+SET preferences = notification_preferences.preferences || EXCLUDED.preferences
+RETURNING user_id;
@@ -0,0 +1,32 @@
+-- name: CreateTaskMessage :one
+INSERT INTO task_messages (task_id, claimer_id, sender_id, body)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListTaskMessages :many
+SELECT * FROM task_messages
+WHERE task_id = @task_id AND claimer_id = @claimer_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: MarkTaskMessagesRead :many
+UPDATE task_messages t
+SET read_at = NOW()
+WHERE t.task_id = @task_id AND t.claimer_id = @claimer_id
+  AND t.sender_id <> @reader_id AND t.read_at IS NULL
+  AND t.created_at <= (
+    SELECT m.created_at FROM task_messages m
+    WHERE m.id = @message_id AND m.task_id = @task_id
+  )
+RETURNING *;
+
+-- name: GetLatestThreadClaimer :one
+-- Returns the claimer of the task's most recent conversation, or of
+-- claimer_id's own conversation when it is set.
+SELECT claimer_id FROM task_messages
+WHERE task_id = @task_id
+  AND (sqlc.narg('claimer_id')::UUID IS NULL OR claimer_id = sqlc.narg('claimer_id')::UUID)
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
@@ -0,0 +1,49 @@
+-- name: CreateNotification :exec
+-- Skipped when the user has turned this type off in their preferences.
+INSERT INTO notifications (user_id, type, task_id, message)
+SELECT p.id, @type::TEXT, sqlc.narg('task_id')::UUID, @message::TEXT
+FROM profiles p
+LEFT JOIN notification_preferences np ON np.user_id = p.id
+WHERE p.id = @user_id
+  AND COALESCE((np.preferences ->> @type::TEXT)::BOOLEAN, TRUE);
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = @user_id
+  AND (NOT @unread_only::BOOLEAN OR read_at IS NULL)
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)::INTEGER as unread_count FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :one
+-- Users who never set a preference get an empty object.
+SELECT COALESCE(np.preferences, '{}')::JSONB as preferences
+FROM profiles p
+LEFT JOIN notification_preferences np ON np.user_id = p.id
+WHERE p.id = $1;
+
+-- name: UpdateNotificationPreferences :one
+-- Merges preferences into the user's stored ones. Returns no rows if the
+-- user has no profile.
+INSERT INTO notification_preferences (user_id, preferences)
+SELECT p.id, @preferences::JSONB FROM profiles p
+WHERE p.id = @user_id
+ON CONFLICT (user_id) DO UPDATE
+SET preferences = notification_preferences.preferences || EXCLUDED.preferences
+RETURNING *;
@@ -7,6 +7,28 @@ RETURNING *;
 SELECT * FROM profiles
 WHERE id = $1;
 
+-- name: GetUserStats :one
+SELECT
+  (SELECT COUNT(*) FROM tasks WHERE requester_id = @user_id::UUID)::INTEGER as tasks_posted,
+  (SELECT COUNT(DISTINCT task_id) FROM task_status_history
+    WHERE action = 'complete' AND actor_id = @user_id::UUID)::INTEGER as tasks_submitted,
+  (SELECT COUNT(*) FROM tasks
+    WHERE claimed_by_id = @user_id::UUID AND status = 'confirmed')::INTEGER as tasks_completed,
+  (SELECT COALESCE(AVG(EXTRACT(EPOCH FROM c.created_at - claimed.at)), 0)
+    FROM task_status_history c
+    CROSS JOIN LATERAL (
+      SELECT MAX(h.created_at) as at FROM task_status_history h
+      WHERE h.task_id = c.task_id AND h.action = 'claim'
+        AND h.actor_id = c.actor_id AND h.created_at <= c.created_at
+    ) claimed
+    WHERE c.action = 'complete' AND c.actor_id = @user_id::UUID)::FLOAT8 as avg_seconds_to_complete,
+  (SELECT COALESCE(SUM(credits), 0) FROM transactions
+    WHERE user_id = @user_id::UUID AND account = 'user' AND kind = 'task_payout')::INTEGER as credits_earned,
+  (SELECT COALESCE(array_agg(DISTINCT skill ORDER BY skill), '{}') FROM tasks
+    WHERE claimed_by_id = @user_id::UUID AND status = 'confirmed')::TEXT[] as skills_used,
+  (SELECT COUNT(*) FROM reviews WHERE reviewee_id = @user_id::UUID)::INTEGER as review_count,
+  (SELECT COALESCE(AVG(rating), 0) FROM reviews WHERE reviewee_id = @user_id::UUID)::FLOAT8 as average_rating;
+
 -- name: UpdateProfile :exec
 UPDATE profiles
 SET name = $2, avatar_url = $3, skills = $4
@@ -29,7 +51,40 @@ WHERE id = $1 AND credits >= $2
 RETURNING *;
 
 -- name: GetLeaderboard :many
-SELECT id, name, avatar_url, credits
-FROM profiles
-ORDER BY credits DESC
-LIMIT $1;
+-- Ranks every user by the chosen metric. Returns the top page_limit users,
+-- plus the caller's own row when caller_id is set and outside the top.
+-- Balances only appear as the score when the metric is balance.
+WITH scores AS (
+  SELECT p.id, p.name, p.avatar_url, r.score AS reputation,
+    (CASE @metric::TEXT
+      WHEN 'earned' THEN (
+        SELECT COALESCE(SUM(tx.credits), 0) FROM transactions tx
+        LEFT JOIN tasks t ON t.id = tx.task_id
+        WHERE tx.user_id = p.id AND tx.account = 'user' AND tx.kind = 'task_payout'
+          AND (sqlc.narg('since')::TIMESTAMPTZ IS NULL OR tx.created_at >= sqlc.narg('since')::TIMESTAMPTZ)
+          AND (sqlc.narg('skill')::TEXT IS NULL OR t.skill = sqlc.narg('skill')::TEXT))
+      WHEN 'completed' THEN (
+        SELECT COUNT(*) FROM tasks t
+        JOIN task_status_history h ON h.task_id = t.id AND h.to_status = 'confirmed'
+        WHERE t.claimed_by_id = p.id AND t.status = 'confirmed'
+          AND (sqlc.narg('since')::TIMESTAMPTZ IS NULL OR h.created_at >= sqlc.narg('since')::TIMESTAMPTZ)
+          AND (sqlc.narg('skill')::TEXT IS NULL OR t.skill = sqlc.narg('skill')::TEXT))
+      ELSE COALESCE(p.credits, 0)
+    END)::INTEGER AS score
+  FROM profiles p
+  JOIN user_reputation r ON r.user_id = p.id
+),
+ranked AS (
+  SELECT *,
+    RANK() OVER (ORDER BY score DESC) AS rank,
+    ROW_NUMBER() OVER (ORDER BY score DESC, id) AS position
+  FROM scores
+)
+SELECT id, name, avatar_url, reputation, score, rank::INTEGER
+FROM ranked
+WHERE position <= @page_limit::INTEGER OR id = sqlc.narg('caller_id')::UUID
+ORDER BY position;
+
+-- name: GetUserReputation :one
+SELECT * FROM user_reputation
+WHERE user_id = $1;
@@ -0,0 +1,17 @@
+-- name: CreateRedemption :one
+INSERT INTO redemptions (user_id, reward_id, transaction_id, cost)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUserRedemptions :many
+SELECT
+  r.*,
+  rw.name as reward_name,
+  rw.planet
+FROM redemptions r
+JOIN rewards rw ON r.reward_id = rw.id
+WHERE r.user_id = @user_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (r.created_at, r.id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT @page_limit;
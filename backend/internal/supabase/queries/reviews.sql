@@ -0,0 +1,17 @@
+-- name: CreateReview :one
+INSERT INTO reviews (task_id, reviewer_id, reviewee_id, rating, body)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (task_id, reviewer_id) DO NOTHING
+RETURNING *;
+
+-- name: ListUserReviews :many
+SELECT
+  r.*,
+  p.name as reviewer_name
+FROM reviews r
+JOIN profiles p ON r.reviewer_id = p.id
+WHERE r.reviewee_id = @reviewee_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (r.created_at, r.id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT @page_limit;
@@ -1,6 +1,9 @@
 -- name: ListRewards :many
 SELECT * FROM rewards
-ORDER BY cost ASC;
+WHERE sqlc.narg('after_cost')::INTEGER IS NULL
+  OR (cost, id) > (sqlc.narg('after_cost')::INTEGER, sqlc.narg('after_id')::INTEGER)
+ORDER BY cost ASC, id ASC
+LIMIT @page_limit;
 
 -- name: GetReward :one
 SELECT * FROM rewards
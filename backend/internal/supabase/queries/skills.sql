@@ -0,0 +1,21 @@
+-- name: ListSkills :many
+SELECT
+  s.slug,
+  s.name,
+  s.category,
+  COALESCE(array_agg(a.alias ORDER BY a.alias) FILTER (WHERE a.alias IS NOT NULL), '{}')::TEXT[] as aliases
+FROM skills s
+LEFT JOIN skill_aliases a ON a.skill_slug = s.slug
+WHERE sqlc.narg('prefix')::TEXT IS NULL
+  OR s.slug LIKE sqlc.narg('prefix')::TEXT || '%'
+  OR lower(s.name) LIKE sqlc.narg('prefix')::TEXT || '%'
+  OR s.slug IN (SELECT skill_slug FROM skill_aliases WHERE alias LIKE sqlc.narg('prefix')::TEXT || '%')
+GROUP BY s.slug
+ORDER BY s.category, s.name;
+
+-- name: ResolveSkill :one
+SELECT * FROM skills
+WHERE slug = @slug::TEXT
+  OR lower(name) = @name::TEXT
+  OR slug IN (SELECT skill_slug FROM skill_aliases WHERE alias = @name::TEXT)
+LIMIT 1;
@@ -0,0 +1,9 @@
+-- name: CreateTaskStatusHistory :one
+INSERT INTO task_status_history (task_id, action, from_status, to_status, actor_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTaskStatusHistory :many
+SELECT * FROM task_status_history
+WHERE task_id = $1
+ORDER BY created_at ASC;
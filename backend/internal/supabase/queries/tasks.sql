@@ -1,6 +1,6 @@
 -- name: CreateTask :one
-INSERT INTO tasks (title, description, skill, urgency, credit_reward, requester_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO tasks (title, description, skill, urgency, credit_reward, requester_id, escrow_amount, due_at, urgency_premium)
+VALUES ($1, $2, $3, $4, $5, $6, $5 + $8, $7, $8)
 RETURNING *;
 
 -- name: GetTask :one
@@ -12,10 +12,50 @@ SELECT * FROM tasks
 ORDER BY created_at DESC
 LIMIT $1;
 
--- name: ListOpenTasks :many
+-- name: ListRecommendationCandidates :many
 SELECT * FROM tasks
-WHERE status = 'open'
-ORDER BY created_at DESC;
+WHERE status = 'open' AND requester_id <> @user_id
+ORDER BY lower(skill) = ANY(@skills::TEXT[]) DESC, created_at DESC
+LIMIT @candidate_limit;
+
+-- name: SearchTasks :many
+SELECT * FROM tasks
+WHERE status = @status::TEXT
+  AND (sqlc.narg('query')::TEXT IS NULL
+    OR to_tsvector('english', title || ' ' || description) @@ websearch_to_tsquery('english', sqlc.narg('query')::TEXT))
+  AND (sqlc.narg('skill')::TEXT IS NULL OR skill = sqlc.narg('skill')::TEXT)
+  AND (sqlc.narg('urgency')::TEXT IS NULL OR urgency = sqlc.narg('urgency')::TEXT)
+  AND (sqlc.narg('min_reward')::INTEGER IS NULL OR credit_reward >= sqlc.narg('min_reward')::INTEGER)
+  AND (sqlc.narg('max_reward')::INTEGER IS NULL OR credit_reward <= sqlc.narg('max_reward')::INTEGER)
+  AND (sqlc.narg('requester_id')::UUID IS NULL OR requester_id = sqlc.narg('requester_id')::UUID)
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (CASE @sort::TEXT
+    WHEN 'reward' THEN credit_reward
+    WHEN 'urgency' THEN CASE urgency WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END
+    ELSE 0
+  END, created_at, id)
+      < (sqlc.narg('after_key')::INTEGER, sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY
+  CASE @sort::TEXT
+    WHEN 'reward' THEN credit_reward
+    WHEN 'urgency' THEN CASE urgency WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END
+    ELSE 0
+  END DESC,
+  created_at DESC,
+  id DESC
+LIMIT @page_limit;
+
+-- name: ListTasksCompletedBefore :many
+SELECT t.* FROM tasks t
+JOIN task_status_history h ON h.task_id = t.id AND h.to_status = 'completed'
+WHERE t.status = 'completed'
+GROUP BY t.id
+HAVING MAX(h.created_at) < @completed_before::TIMESTAMPTZ;
+
+-- name: ListTasksDueBefore :many
+SELECT * FROM tasks
+WHERE status IN ('open', 'claimed') AND due_at < @due_before::TIMESTAMPTZ
+ORDER BY due_at;
 
 -- name: ListTasksBySkill :many
 SELECT * FROM tasks
@@ -24,34 +64,111 @@ ORDER BY credit_reward DESC;
 
 -- name: ListTasksByRequester :many
 SELECT * FROM tasks
-WHERE requester_id = $1
-ORDER BY created_at DESC;
+WHERE requester_id = @requester_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
 
 -- name: ListTasksByClaimer :many
 SELECT * FROM tasks
-WHERE claimed_by_id = $1
-ORDER BY created_at DESC;
+WHERE claimed_by_id = @claimed_by_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
 
--- name: ClaimTask :exec
+-- name: ClaimTask :one
 UPDATE tasks
 SET claimed_by_id = $2, status = 'claimed'
-WHERE id = $1 AND status = 'open';
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: ReleaseTask :one
+UPDATE tasks
+SET claimed_by_id = NULL, status = 'open'
+WHERE id = $1 AND status = 'claimed' AND claimed_by_id = $2
+RETURNING *;
+
+-- name: ExpireTask :one
+UPDATE tasks
+SET status = 'expired'
+WHERE id = $1 AND status = 'open'
+RETURNING *;
 
--- name: CompleteTask :exec
+-- name: ExpireClaim :one
+UPDATE tasks
+SET claimed_by_id = NULL, status = 'open', due_at = NULL
+WHERE id = $1 AND status = 'claimed' AND claimed_by_id = $2
+RETURNING *;
+
+-- name: CompleteTask :one
 UPDATE tasks
 SET status = 'completed'
-WHERE id = $1 AND status = 'claimed';
+WHERE id = $1 AND status = 'claimed'
+RETURNING *;
 
--- name: ConfirmTask :exec
+-- name: RejectTask :one
+UPDATE tasks
+SET
+  status = CASE WHEN rejection_count + 1 >= @max_rejections::INTEGER THEN 'disputed' ELSE 'claimed' END,
+  rejection_reason = @rejection_reason,
+  rejection_count = rejection_count + 1
+WHERE id = @id AND status = 'completed'
+RETURNING *;
+
+-- name: DisputeTask :one
+UPDATE tasks
+SET status = 'disputed'
+WHERE id = $1 AND status = $2
+RETURNING *;
+
+-- name: ResolveTask :one
+UPDATE tasks
+SET status = $2
+WHERE id = $1 AND status = 'disputed'
+RETURNING *;
+
+-- name: ConfirmTask :one
 UPDATE tasks
 SET status = 'confirmed'
-WHERE id = $1 AND status = 'completed';
+WHERE id = $1 AND status = 'completed'
+RETURNING *;
 
--- name: CancelTask :exec
+-- name: CancelTask :one
 UPDATE tasks
 SET status = 'cancelled', claimed_by_id = NULL
-WHERE id = $1;
+WHERE id = $1 AND status = $2
+RETURNING *;
 
--- name: DeleteTask :exec
+-- name: DeleteTask :one
 DELETE FROM tasks
-WHERE id = $1 AND status = 'open';
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: SettleEscrow :one
+UPDATE tasks t
+SET escrow_amount = 0, escrow_state = $2
+FROM (SELECT l.id, l.escrow_amount FROM tasks l WHERE l.id = $1 FOR UPDATE) held
+WHERE t.id = held.id AND t.escrow_state = 'held'
+RETURNING held.escrow_amount;
+
+-- name: GetEscrowTotal :one
+SELECT COALESCE(SUM(escrow_amount), 0)::INTEGER as total
+FROM tasks
+WHERE escrow_state = 'held';
+
+-- name: GetEscrowMismatches :many
+SELECT
+  t.id,
+  t.credit_reward,
+  t.escrow_amount,
+  t.escrow_state,
+  COALESCE(SUM(tx.credits), 0)::INTEGER as ledger_amount
+FROM tasks t
+LEFT JOIN transactions tx ON tx.task_id = t.id AND tx.account = 'escrow'
+GROUP BY t.id
+HAVING
+  t.escrow_amount <> COALESCE(SUM(tx.credits), 0)
+  OR (t.escrow_state = 'held' AND t.escrow_amount <> t.credit_reward + t.urgency_premium)
+  OR (t.escrow_state <> 'held' AND t.escrow_amount <> 0);
@@ -1,13 +1,15 @@
 -- name: CreateTransaction :one
-INSERT INTO transactions (user_id, task_id, credits)
-VALUES ($1, $2, $3)
+INSERT INTO transactions (user_id, task_id, credits, kind, account, entry_id)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetUserTransactions :many
 SELECT * FROM transactions
-WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2;
+WHERE user_id = @user_id
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
 
 -- name: GetTaskTransactions :many
 SELECT * FROM transactions
@@ -22,3 +24,19 @@ FROM transactions t
 JOIN profiles p ON t.user_id = p.id
 ORDER BY t.created_at DESC
 LIMIT $1;
+
+-- name: GetBalanceMismatches :many
+SELECT
+  p.id,
+  p.credits,
+  COALESCE(SUM(t.credits), 0)::INTEGER as ledger_credits
+FROM profiles p
+LEFT JOIN transactions t ON t.user_id = p.id AND t.account = 'user'
+GROUP BY p.id
+HAVING p.credits IS DISTINCT FROM COALESCE(SUM(t.credits), 0)::INTEGER;
+
+-- name: GetUnbalancedEntries :many
+SELECT entry_id, SUM(credits)::INTEGER as total
+FROM transactions
+GROUP BY entry_id
+HAVING SUM(credits) <> 0;
@@ -0,0 +1,71 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (owner_id, url, secret, event_types, all_events)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooksByOwner :many
+SELECT * FROM webhooks
+WHERE owner_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues the event for every webhook subscribed to it that either receives
+-- all events or belongs to one of user_ids.
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+SELECT w.id, @event_type::TEXT, @payload::JSONB
+FROM webhooks w
+WHERE @event_type::TEXT = ANY(w.event_types)
+  AND (w.all_events OR w.owner_id = ANY(@user_ids::UUID[]));
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Leases up to batch_size due deliveries by pushing their next attempt to
+-- lease_until, so concurrent workers skip them while they are being sent.
+UPDATE webhook_deliveries d
+SET next_attempt_at = @lease_until::TIMESTAMPTZ
+FROM webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING d.id, d.event_type, d.payload, d.attempts, w.url, w.secret;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded', attempts = attempts + 1, response_status = $2,
+  last_error = NULL, delivered_at = NOW()
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, status = @status, response_status = sqlc.narg('response_status'),
+  last_error = @last_error, next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = @webhook_id
+  AND (sqlc.narg('status')::TEXT IS NULL OR status = sqlc.narg('status')::TEXT)
+  AND (sqlc.narg('after_created_at')::TIMESTAMPTZ IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::TIMESTAMPTZ, sqlc.narg('after_id')::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: ReplayWebhookDelivery :one
+-- Queues a fresh copy of a delivery, leaving the original's record intact.
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+SELECT d.webhook_id, d.event_type, d.payload
+FROM webhook_deliveries d
+WHERE d.id = @id AND d.webhook_id = @webhook_id
+RETURNING *;
@@ -7,27 +7,155 @@ CREATE TABLE profiles (
   created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Canonical skills. Tasks and profiles store slugs; aliases map the other
+-- spellings users type (stored lowercase) onto them.
+CREATE TABLE skills (
+  slug TEXT PRIMARY KEY CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+  name TEXT NOT NULL,
+  category TEXT NOT NULL DEFAULT 'other'
+);
+
+CREATE TABLE skill_aliases (
+  alias TEXT PRIMARY KEY CHECK (alias = lower(alias)),
+  skill_slug TEXT NOT NULL REFERENCES skills(slug) ON DELETE CASCADE
+);
+
 CREATE TABLE tasks (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   title TEXT NOT NULL,
   description TEXT NOT NULL,
-  skill TEXT NOT NULL,
-  urgency TEXT DEFAULT 'medium',
+  skill TEXT NOT NULL REFERENCES skills(slug),
+  urgency TEXT NOT NULL DEFAULT 'medium'
+    CHECK (urgency IN ('low', 'medium', 'high', 'critical')),
   credit_reward INTEGER NOT NULL CHECK (credit_reward > 0),
   requester_id UUID NOT NULL REFERENCES profiles(id),
   claimed_by_id UUID REFERENCES profiles(id),
   status TEXT DEFAULT 'open',
-  created_at TIMESTAMPTZ DEFAULT NOW()
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  escrow_amount INTEGER NOT NULL DEFAULT 0 CHECK (escrow_amount >= 0),
+  escrow_state TEXT NOT NULL DEFAULT 'held'
+    CHECK (escrow_state IN ('held', 'released', 'refunded', 'split')),
+  rejection_reason TEXT,
+  rejection_count INTEGER NOT NULL DEFAULT 0,
+  due_at TIMESTAMPTZ,
+  urgency_premium INTEGER NOT NULL DEFAULT 0 CHECK (urgency_premium >= 0)
 );
 
+-- Double-entry ledger: rows sharing entry_id sum to zero. User rows carry
+-- user_id; escrow and system rows are the counter accounts.
 CREATE TABLE transactions (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-  user_id UUID NOT NULL REFERENCES profiles(id),
-  task_id UUID REFERENCES tasks(id),
+  user_id UUID REFERENCES profiles(id),
+  task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
   credits INTEGER NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  kind TEXT NOT NULL CHECK (kind IN (
+    'task_escrow', 'task_refund', 'task_payout',
+    'signup_bonus', 'redemption', 'admin_adjustment'
+  )),
+  account TEXT NOT NULL CHECK (account IN ('user', 'escrow', 'system')),
+  entry_id UUID NOT NULL,
+  CHECK ((account = 'user') = (user_id IS NOT NULL))
+);
+
+CREATE TABLE task_status_history (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  action TEXT NOT NULL,
+  from_status TEXT,
+  to_status TEXT NOT NULL,
+  actor_id UUID REFERENCES profiles(id),
   created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+CREATE TABLE disputes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id),
+  opened_by_id UUID NOT NULL REFERENCES profiles(id),
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+  outcome TEXT CHECK (outcome IN ('award', 'refund', 'split')),
+  claimer_percent INTEGER CHECK (claimer_percent BETWEEN 0 AND 100),
+  resolution_note TEXT,
+  resolved_by_id UUID REFERENCES profiles(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE TABLE reviews (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  reviewer_id UUID NOT NULL REFERENCES profiles(id),
+  reviewee_id UUID NOT NULL REFERENCES profiles(id),
+  rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (task_id, reviewer_id)
+);
+
+CREATE TABLE notifications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+  message TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  read_at TIMESTAMPTZ,
+  emailed_at TIMESTAMPTZ
+);
+
+-- Email addresses for notification emails and digests. Kept out of
+-- profiles, which anyone can read, and copied from auth.users by the
+-- triggers below.
+CREATE TABLE user_emails (
+  user_id UUID PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  unsubscribe_token UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  digest_sent_at TIMESTAMPTZ
+);
+
+-- Which notification and email types each user has turned on or off. Kept
+-- out of profiles, which anyone can read; users without a row have every
+-- type on.
+CREATE TABLE notification_preferences (
+  user_id UUID PRIMARY KEY REFERENCES profiles(id) ON DELETE CASCADE,
+  preferences JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE webhooks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id UUID NOT NULL REFERENCES profiles(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  all_events BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'dead')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  response_status INTEGER,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  delivered_at TIMESTAMPTZ
+);
+
+CREATE TABLE task_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  claimer_id UUID NOT NULL REFERENCES profiles(id),
+  sender_id UUID NOT NULL REFERENCES profiles(id),
+  body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 2000),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  read_at TIMESTAMPTZ
+);
+
 CREATE TABLE rewards (
   id SERIAL PRIMARY KEY,
   name TEXT NOT NULL,
@@ -36,6 +164,40 @@ CREATE TABLE rewards (
   description TEXT
 );
 
+CREATE TABLE redemptions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES profiles(id),
+  reward_id INTEGER NOT NULL REFERENCES rewards(id),
+  transaction_id UUID NOT NULL REFERENCES transactions(id),
+  cost INTEGER NOT NULL CHECK (cost > 0),
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Reputation inputs and score per user. Confirmed work and good ratings
+-- earn points; walking away from claimed work or cancelling someone's
+-- in-progress work costs them. Trust levels are derived from score in Go.
+CREATE VIEW user_reputation AS
+SELECT
+  p.id AS user_id,
+  c.tasks_confirmed,
+  c.rating_points,
+  c.releases,
+  c.cancellations,
+  GREATEST(10 * c.tasks_confirmed + 5 * c.rating_points - 5 * c.releases - 8 * c.cancellations, 0)::INTEGER AS score
+FROM profiles p
+CROSS JOIN LATERAL (
+  SELECT
+    (SELECT COUNT(*) FROM tasks t
+      WHERE t.claimed_by_id = p.id AND t.status = 'confirmed')::INTEGER AS tasks_confirmed,
+    (SELECT COALESCE(SUM(r.rating - 3), 0) FROM reviews r
+      WHERE r.reviewee_id = p.id)::INTEGER AS rating_points,
+    (SELECT COUNT(*) FROM task_status_history h
+      WHERE h.actor_id = p.id AND h.action = 'release')::INTEGER AS releases,
+    (SELECT COUNT(*) FROM task_status_history h
+      WHERE h.actor_id = p.id AND h.action = 'cancel'
+        AND h.from_status IN ('claimed', 'completed'))::INTEGER AS cancellations
+) c;
+
 -- SEED DATA
 INSERT INTO rewards (name, planet, cost, description) VALUES
   ('Mars Express', 'Mars', 1000, 'Quick trip to the red planet'),
@@ -44,17 +206,92 @@ INSERT INTO rewards (name, planet, cost, description) VALUES
   ('Weekend Mars Getaway', 'Mars', 500, '3-day surface visit'),
   ('Asteroid Mining Tour', 'Asteroid Belt', 3000, 'Zero-g mining experience');
 
+INSERT INTO skills (slug, name, category) VALUES
+  ('go', 'Go', 'development'),
+  ('javascript', 'JavaScript', 'development'),
+  ('typescript', 'TypeScript', 'development'),
+  ('python', 'Python', 'development'),
+  ('rust', 'Rust', 'development'),
+  ('java', 'Java', 'development'),
+  ('react', 'React', 'development'),
+  ('sql', 'SQL', 'development'),
+  ('devops', 'DevOps', 'development'),
+  ('ui-design', 'UI Design', 'design'),
+  ('graphic-design', 'Graphic Design', 'design'),
+  ('copywriting', 'Copywriting', 'writing'),
+  ('editing', 'Editing', 'writing'),
+  ('translation', 'Translation', 'writing'),
+  ('data-analysis', 'Data Analysis', 'data'),
+  ('machine-learning', 'Machine Learning', 'data'),
+  ('seo', 'SEO', 'marketing'),
+  ('social-media', 'Social Media', 'marketing'),
+  ('other', 'Other', 'other');
+
+INSERT INTO skill_aliases (alias, skill_slug) VALUES
+  ('golang', 'go'),
+  ('js', 'javascript'),
+  ('node', 'javascript'),
+  ('nodejs', 'javascript'),
+  ('ts', 'typescript'),
+  ('py', 'python'),
+  ('reactjs', 'react'),
+  ('react.js', 'react'),
+  ('postgres', 'sql'),
+  ('postgresql', 'sql'),
+  ('ui', 'ui-design'),
+  ('ux', 'ui-design'),
+  ('ux design', 'ui-design'),
+  ('design', 'graphic-design'),
+  ('writing', 'copywriting'),
+  ('proofreading', 'editing'),
+  ('ml', 'machine-learning'),
+  ('ai', 'machine-learning'),
+  ('analytics', 'data-analysis');
+
 -- INDEXES
 CREATE INDEX idx_tasks_status ON tasks(status);
 CREATE INDEX idx_tasks_requester ON tasks(requester_id);
 CREATE INDEX idx_tasks_claimed_by ON tasks(claimed_by_id);
+CREATE INDEX idx_tasks_escrow_state ON tasks(escrow_state);
+CREATE INDEX idx_tasks_due_at ON tasks(due_at) WHERE status IN ('open', 'claimed');
+CREATE INDEX idx_tasks_status_created ON tasks(status, created_at DESC);
+CREATE INDEX idx_tasks_status_reward ON tasks(status, credit_reward DESC);
+CREATE INDEX idx_tasks_status_skill ON tasks(status, skill);
+CREATE INDEX idx_tasks_search ON tasks
+  USING GIN (to_tsvector('english', title || ' ' || description));
+CREATE INDEX idx_task_status_history_task ON task_status_history(task_id, created_at);
+CREATE UNIQUE INDEX idx_disputes_open_task ON disputes(task_id) WHERE status = 'open';
 CREATE INDEX idx_profiles_credits ON profiles(credits DESC);
+CREATE INDEX idx_transactions_user ON transactions(user_id, created_at DESC);
+CREATE INDEX idx_transactions_entry ON transactions(entry_id);
+CREATE INDEX idx_skill_aliases_skill ON skill_aliases(skill_slug);
+CREATE INDEX idx_reviews_reviewee ON reviews(reviewee_id, created_at DESC);
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE INDEX idx_notifications_unemailed ON notifications(created_at) WHERE emailed_at IS NULL;
+CREATE INDEX idx_webhooks_owner ON webhooks(owner_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX idx_task_messages_thread ON task_messages(task_id, claimer_id, created_at DESC);
+CREATE INDEX idx_redemptions_user ON redemptions(user_id, created_at DESC);
 
 -- ROW LEVEL SECURITY
 ALTER TABLE profiles ENABLE ROW LEVEL SECURITY;
 ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
 ALTER TABLE transactions ENABLE ROW LEVEL SECURITY;
 ALTER TABLE rewards ENABLE ROW LEVEL SECURITY;
+ALTER TABLE redemptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_status_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE disputes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE skills ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reviews ENABLE ROW LEVEL SECURITY;
+ALTER TABLE skill_aliases ENABLE ROW LEVEL SECURITY;
+ALTER TABLE task_messages ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_emails ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_preferences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
 
 -- PROFILES POLICIES
 CREATE POLICY "Anyone can view profiles"
@@ -95,3 +332,92 @@ CREATE POLICY "Users can view their own transactions"
 CREATE POLICY "Anyone can view rewards"
   ON rewards FOR SELECT
   USING (true);
+
+-- REDEMPTIONS POLICIES
+CREATE POLICY "Users can view their own redemptions"
+  ON redemptions FOR SELECT
+  USING (auth.uid() = user_id);
+
+-- TASK STATUS HISTORY POLICIES
+CREATE POLICY "Anyone can view task status history"
+  ON task_status_history FOR SELECT
+  USING (true);
+
+-- DISPUTES POLICIES
+CREATE POLICY "Task parties can view disputes"
+  ON disputes FOR SELECT
+  USING (EXISTS (
+    SELECT 1 FROM tasks
+    WHERE tasks.id = disputes.task_id
+      AND (auth.uid() = tasks.requester_id OR auth.uid() = tasks.claimed_by_id)
+  ));
+
+-- SKILLS POLICIES (read-only)
+CREATE POLICY "Anyone can view skills"
+  ON skills FOR SELECT
+  USING (true);
+
+CREATE POLICY "Anyone can view skill aliases"
+  ON skill_aliases FOR SELECT
+  USING (true);
+
+-- REVIEWS POLICIES
+CREATE POLICY "Anyone can view reviews"
+  ON reviews FOR SELECT
+  USING (true);
+
+-- NOTIFICATIONS POLICIES
+CREATE POLICY "Users can view their own notifications"
+  ON notifications FOR SELECT
+  USING (auth.uid() = user_id);
+
+-- USER EMAILS POLICIES
+CREATE POLICY "Users can view their own email settings"
+  ON user_emails FOR SELECT
+  USING (auth.uid() = user_id);
+
+-- NOTIFICATION PREFERENCES POLICIES
+CREATE POLICY "Users can view their own notification preferences"
+  ON notification_preferences FOR SELECT
+  USING (auth.uid() = user_id);
+
+-- WEBHOOKS POLICIES
+CREATE POLICY "Users can view their own webhooks"
+  ON webhooks FOR SELECT
+  USING (auth.uid() = owner_id);
+
+CREATE POLICY "Users can view deliveries to their webhooks"
+  ON webhook_deliveries FOR SELECT
+  USING (EXISTS (
+    SELECT 1 FROM webhooks
+    WHERE webhooks.id = webhook_deliveries.webhook_id AND auth.uid() = webhooks.owner_id
+  ));
+
+-- TASK MESSAGES POLICIES
+CREATE POLICY "Task parties can view their messages"
+  ON task_messages FOR SELECT
+  USING (auth.uid() = claimer_id OR EXISTS (
+    SELECT 1 FROM tasks
+    WHERE tasks.id = task_messages.task_id AND auth.uid() = tasks.requester_id
+  ));
+
+-- TRIGGERS
+CREATE FUNCTION sync_user_email() RETURNS TRIGGER
+LANGUAGE plpgsql SECURITY DEFINER SET search_path = public AS $$
+BEGIN
+  INSERT INTO user_emails (user_id, email)
+  SELECT p.id, u.email FROM profiles p
+  JOIN auth.users u ON u.id = p.id
+  WHERE p.id = NEW.id AND u.email IS NOT NULL
+  ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email;
+  RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER sync_email_on_profile_insert
+  AFTER INSERT ON profiles
+  FOR EACH ROW EXECUTE FUNCTION sync_user_email();
+
+CREATE TRIGGER sync_email_on_auth_update
+  AFTER UPDATE OF email ON auth.users
+  FOR EACH ROW EXECUTE FUNCTION sync_user_email();
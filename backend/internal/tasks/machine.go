@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Status is the lifecycle state stored in tasks.status.
+type Status string
+
+const (
+	Open      Status = "open"
+	Claimed   Status = "claimed"
+	Completed Status = "completed"
+	Confirmed Status = "confirmed"
+	Cancelled Status = "cancelled"
+	Disputed  Status = "disputed"
+	Expired   Status = "expired"
+
+	// Deleted is never stored; the task row is removed instead.
+	Deleted Status = "deleted"
+)
+
+// Action is something a user or the system does to a task.
+type Action string
+
+const (
+	Create   Action = "create"
+	Claim    Action = "claim"
+	Release  Action = "release"
+	Complete Action = "complete"
+	Reject   Action = "reject"
+	Confirm  Action = "confirm"
+	Cancel   Action = "cancel"
+	Dispute  Action = "dispute"
+	Resolve  Action = "resolve"
+	Delete   Action = "delete"
+)
+
+// Actions the system performs on its own schedule.
+const (
+	// AutoConfirm confirms completed work the requester left unconfirmed
+	// past the grace period.
+	AutoConfirm Action = "auto_confirm"
+
+	// Expire closes an open task whose deadline passed before anyone claimed it.
+	Expire Action = "expire"
+
+	// ExpireClaim takes an overdue task back from its claimer and reopens it.
+	ExpireClaim Action = "expire_claim"
+)
+
+// Role is how an actor relates to a task.
+type Role string
+
+const (
+	Requester Role = "requester"
+	Claimer   Role = "claimer"
+	Other     Role = "other"
+	System    Role = "system"
+	Admin     Role = "admin"
+)
+
+// Transition describes a legal move between statuses and who may make it.
+type Transition struct {
+	Action Action
+	From   []Status
+	To     Status
+	Roles  []Role
+}
+
+// MaxRejections is how many times a requester may reject completed work
+// before the task is escalated to Disputed instead of going back to Claimed.
+const MaxRejections = 3
+
+// transitions lists every legal move. Where an action can end in more than
+// one status, To is the usual outcome: a rejection can escalate to Disputed,
+// and resolving a dispute with a full refund cancels the task. Requesters
+// can only cancel before work is delivered; after that they reject or
+// dispute it instead.
+var transitions = map[Action]Transition{
+	Claim:       {Claim, []Status{Open}, Claimed, []Role{Other}},
+	Release:     {Release, []Status{Claimed}, Open, []Role{Claimer}},
+	Complete:    {Complete, []Status{Claimed}, Completed, []Role{Claimer}},
+	Reject:      {Reject, []Status{Completed}, Claimed, []Role{Requester}},
+	Confirm:     {Confirm, []Status{Completed}, Confirmed, []Role{Requester}},
+	AutoConfirm: {AutoConfirm, []Status{Completed}, Confirmed, []Role{System}},
+	Expire:      {Expire, []Status{Open}, Expired, []Role{System}},
+	ExpireClaim: {ExpireClaim, []Status{Claimed}, Open, []Role{System}},
+	Cancel:      {Cancel, []Status{Open, Claimed}, Cancelled, []Role{Requester}},
+	Delete:      {Delete, []Status{Open}, Deleted, []Role{Requester}},
+	Dispute:     {Dispute, []Status{Claimed, Completed}, Disputed, []Role{Requester, Claimer}},
+	Resolve:     {Resolve, []Status{Disputed}, Confirmed, []Role{Admin}},
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid task transition")
+	ErrNotPermitted      = errors.New("not permitted to perform this action")
+)
+
+// TransitionError explains why an action was refused.
+type TransitionError struct {
+	Action Action
+	From   Status
+	Role   Role
+	Err    error
+}
+
+func (e *TransitionError) Error() string {
+	if errors.Is(e.Err, ErrNotPermitted) {
+		return fmt.Sprintf("%s may not %s this task", e.Role, e.Action)
+	}
+	return fmt.Sprintf("cannot %s a task that is %s", e.Action, e.From)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// Next returns the status a task in from moves to when role performs action.
+// It returns a *TransitionError if the move is illegal or role may not make it.
+func Next(from Status, action Action, role Role) (Status, error) {
+	t, ok := transitions[action]
+	if !ok || !slices.Contains(t.From, from) {
+		return "", &TransitionError{Action: action, From: from, Role: role, Err: ErrInvalidTransition}
+	}
+
+	if !slices.Contains(t.Roles, role) {
+		return "", &TransitionError{Action: action, From: from, Role: role, Err: ErrNotPermitted}
+	}
+
+	return t.To, nil
+}
+
+// StatusOf returns the task's status, treating an unset status as open.
+func StatusOf(task generated.Task) Status {
+	if !task.Status.Valid || task.Status.String == "" {
+		return Open
+	}
+	return Status(task.Status.String)
+}
+
+// RoleOf returns how actorID relates to the task. An invalid actorID is the system.
+func RoleOf(task generated.Task, actorID pgtype.UUID) Role {
+	switch {
+	case !actorID.Valid:
+		return System
+	case actorID == task.RequesterID:
+		return Requester
+	case task.ClaimedByID.Valid && actorID == task.ClaimedByID:
+		return Claimer
+	}
+	return Other
+}
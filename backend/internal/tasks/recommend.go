@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	generated "github.com/egeuysall/summit/internal/supabase/generated"
+)
+
+// Weights of each signal in a recommendation score. They sum to 1, so a
+// score is between 0 and 1.
+const (
+	skillWeight   = 0.55
+	rewardWeight  = 0.20
+	urgencyWeight = 0.15
+	recencyWeight = 0.10
+)
+
+// recencyHalfLife is the task age at which its recency signal halves.
+const recencyHalfLife = 72 * time.Hour
+
+// Recommendation is an open task ranked for a user, with the reasons it
+// ranked where it did.
+type Recommendation struct {
+	Task          generated.Task
+	Score         float64
+	MatchedSkills []string
+}
+
+// Recommend scores candidates against the user's skills and returns them
+// best first. Skill overlap dominates; reward (relative to the best-paying
+// candidate), urgency and recency break ties between equally good matches.
+func Recommend(candidates []generated.Task, skills []string, now time.Time) []Recommendation {
+	var maxReward int32
+	for _, t := range candidates {
+		maxReward = max(maxReward, t.CreditReward)
+	}
+
+	recs := make([]Recommendation, len(candidates))
+	for i, t := range candidates {
+		recs[i] = score(t, skills, maxReward, now)
+	}
+
+	slices.SortStableFunc(recs, func(a, b Recommendation) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return recs
+}
+
+func score(t generated.Task, skills []string, maxReward int32, now time.Time) Recommendation {
+	rec := Recommendation{Task: t, MatchedSkills: []string{}}
+
+	for _, s := range skills {
+		if strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(t.Skill)) {
+			rec.MatchedSkills = append(rec.MatchedSkills, t.Skill)
+			break
+		}
+	}
+
+	var skill, reward, recency float64
+	if len(rec.MatchedSkills) > 0 {
+		skill = 1
+	}
+	if maxReward > 0 {
+		reward = float64(t.CreditReward) / float64(maxReward)
+	}
+	if t.CreatedAt.Valid {
+		age := max(now.Sub(t.CreatedAt.Time), 0)
+		recency = math.Exp2(-age.Hours() / recencyHalfLife.Hours())
+	}
+	urgency := float64(max(Urgency(t.Urgency).Rank()-1, 0)) / float64(len(Urgencies)-1)
+
+	rec.Score = skillWeight*skill + rewardWeight*reward + urgencyWeight*urgency + recencyWeight*recency
+	rec.Score = math.Round(rec.Score*1000) / 1000
+	return rec
+}
@@ -0,0 +1,59 @@
+package tasks
+
+// TrustLevel is how far a user's reputation lets them go on the marketplace.
+type TrustLevel int
+
+const (
+	TrustNew TrustLevel = iota
+	TrustBasic
+	TrustTrusted
+	TrustVeteran
+)
+
+// trustThresholds is the minimum reputation score for each level, indexed
+// by level. The score itself is computed by the user_reputation view.
+var trustThresholds = []int32{0, 20, 60, 150}
+
+// claimRequirements is the level needed to claim a task, from the highest
+// reward bracket down.
+var claimRequirements = []struct {
+	minReward int32
+	level     TrustLevel
+}{
+	{2000, TrustTrusted},
+	{500, TrustBasic},
+}
+
+func (l TrustLevel) String() string {
+	switch l {
+	case TrustBasic:
+		return "basic"
+	case TrustTrusted:
+		return "trusted"
+	case TrustVeteran:
+		return "veteran"
+	}
+	return "new"
+}
+
+// TrustLevelFor maps a reputation score to its trust level.
+func TrustLevelFor(score int32) TrustLevel {
+	level := TrustNew
+	for l, threshold := range trustThresholds {
+		if score >= threshold {
+			level = TrustLevel(l)
+		}
+	}
+	return level
+}
+
+// RequiredTrustLevel is the level a user needs to claim a task with the
+// given reward.
+func RequiredTrustLevel(reward int32) TrustLevel {
+	for _, req := range claimRequirements {
+		if reward >= req.minReward {
+			return req.level
+		}
+	}
+	return TrustNew
+}
@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"math"
+	"slices"
+)
+
+// Urgency is how soon a requester needs a task done, stored in tasks.urgency.
+type Urgency string
+
+const (
+	UrgencyLow      Urgency = "low"
+	UrgencyMedium   Urgency = "medium"
+	UrgencyHigh     Urgency = "high"
+	UrgencyCritical Urgency = "critical"
+)
+
+// Urgencies lists every urgency from least to most urgent.
+var Urgencies = []Urgency{UrgencyLow, UrgencyMedium, UrgencyHigh, UrgencyCritical}
+
+// MaxReward is the largest credit reward a task may offer. It leaves room
+// for the largest premium, so a task's escrow always fits in an int32.
+const MaxReward = math.MaxInt32 / 2
+
+// premiumPercent is the share of the reward a requester adds on top when
+// they opt into an urgency premium. Only urgent work carries one.
+var premiumPercent = map[Urgency]int32{
+	UrgencyHigh:     25,
+	UrgencyCritical: 50,
+}
+
+// ParseUrgency returns the urgency named by s, if there is one.
+func ParseUrgency(s string) (Urgency, bool) {
+	u := Urgency(s)
+	return u, slices.Contains(Urgencies, u)
+}
+
+// Rank orders urgencies from 1 (low) to 4 (critical); unknown values rank 0.
+// The task feed's urgency sort uses the same ranking.
+func (u Urgency) Rank() int32 {
+	return int32(slices.Index(Urgencies, u) + 1)
+}
+
+// Premium is the extra escrow for a task of this urgency with the given
+// reward, rounded down. It is zero for low and medium urgency.
+func (u Urgency) Premium(reward int32) int32 {
+	return int32(int64(reward) * int64(premiumPercent[u]) / 100)
+}
@@ -0,0 +1,37 @@
+package tasks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPremium(t *testing.T) {
+	tests := []struct {
+		urgency Urgency
+		reward  int32
+		want    int32
+	}{
+		{UrgencyLow, 100, 0},
+		{UrgencyMedium, 100, 0},
+		{UrgencyHigh, 100, 25},
+		{UrgencyCritical, 100, 50},
+		{UrgencyHigh, 3, 0},
+		{UrgencyHigh, MaxReward, MaxReward / 4},
+		{UrgencyCritical, MaxReward, MaxReward / 2},
+		{UrgencyCritical, math.MaxInt32, math.MaxInt32 / 2},
+	}
+
+	for _, tt := range tests {
+		if got := tt.urgency.Premium(tt.reward); got != tt.want {
+			t.Errorf("%s.Premium(%d) = %d, want %d", tt.urgency, tt.reward, got, tt.want)
+		}
+	}
+}
+
+func TestMaxRewardEscrowFits(t *testing.T) {
+	for _, u := range Urgencies {
+		if escrow := int64(MaxReward) + int64(u.Premium(MaxReward)); escrow > math.MaxInt32 {
+			t.Errorf("%s: escrow for MaxReward is %d, over the int32 limit", u, escrow)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be between 1 and 100")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Cursor identifies the last row of a page. Rows are ordered by
+// (Key, CreatedAt, ID); Key is only set by lists that sort on something
+// other than recency, such as reward or cost.
+type Cursor struct {
+	Key       int32     `json:"k,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+}
+
+// Encode returns the cursor as an opaque string for clients to send back.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Page is a client's request for one page of a list.
+type Page struct {
+	Limit int32
+	After *Cursor
+}
+
+// ParsePage reads the "limit" (default: 20, max: 100) and "cursor" query
+// parameters.
+func ParsePage(r *http.Request) (Page, error) {
+	limit, err := ParseLimit(r, DefaultPageLimit)
+	if err != nil {
+		return Page{}, err
+	}
+	page := Page{Limit: limit}
+
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		b, err := base64.RawURLEncoding.DecodeString(cursorStr)
+		if err != nil {
+			return Page{}, ErrInvalidCursor
+		}
+
+		var cursor Cursor
+		if err := json.Unmarshal(b, &cursor); err != nil || cursor.ID == "" {
+			return Page{}, ErrInvalidCursor
+		}
+		page.After = &cursor
+	}
+
+	return page, nil
+}
+
+// ParseUUIDPage is ParsePage for lists whose rows have UUIDs, and also
+// rejects cursors whose ID is not one.
+func ParseUUIDPage(r *http.Request) (Page, error) {
+	page, err := ParsePage(r)
+	if err != nil || page.After == nil {
+		return page, err
+	}
+	if _, err := ParseUUID(page.After.ID); err != nil {
+		return Page{}, ErrInvalidCursor
+	}
+	return page, nil
+}
+
+// ParseLimit reads the "limit" query parameter (max: 100), for lists that
+// are capped but not paginated. It returns def when no limit is given.
+func ParseLimit(r *http.Request, def int32) (int32, error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return def, nil
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 32)
+	if err != nil || limit < 1 || limit > MaxPageLimit {
+		return 0, ErrInvalidLimit
+	}
+	return int32(limit), nil
+}
+
+// FetchLimit is the number of rows to query: one more than the page holds,
+// so Paginate can tell whether another page follows.
+func (p Page) FetchLimit() int32 {
+	return p.Limit + 1
+}
+
+// AfterKey is the cursor's sort key, or NULL on the first page.
+func (p Page) AfterKey() pgtype.Int4 {
+	if p.After == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: p.After.Key, Valid: true}
+}
+
+// AfterCreatedAt is the cursor's timestamp, or NULL on the first page.
+func (p Page) AfterCreatedAt() pgtype.Timestamptz {
+	if p.After == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: p.After.CreatedAt, Valid: true}
+}
+
+// AfterID is the cursor's row ID, or NULL on the first page. It is for pages
+// from ParseUUIDPage, which has checked that the ID is a UUID.
+func (p Page) AfterID() pgtype.UUID {
+	if p.After == nil {
+		return pgtype.UUID{}
+	}
+	id, _ := ParseUUID(p.After.ID)
+	return id
+}
+
+// Paginate trims rows fetched with FetchLimit down to the page and returns
+// the cursor for the next page, or "" if this is the last one.
+func Paginate[T any](rows []T, page Page, cursor func(T) Cursor) ([]T, string) {
+	if int32(len(rows)) <= page.Limit {
+		return rows, ""
+	}
+
+	rows = rows[:page.Limit]
+	return rows, cursor(rows[len(rows)-1]).Encode()
+}
+
+// SendPage writes one page of a list. next_cursor is null on the last page.
+func SendPage(w http.ResponseWriter, data interface{}, nextCursor string, statusCode int) {
+	var next *string
+	if nextCursor != "" {
+		next = &nextCursor
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        data,
+		"next_cursor": next,
+		"has_more":    next != nil,
+	})
+}
@@ -20,6 +20,17 @@ func SendJson(w http.ResponseWriter, data interface{}, statusCode int) {
 	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
 }
 
+// SendJsonWithMeta writes data like SendJson, with meta's keys alongside it.
+func SendJsonWithMeta(w http.ResponseWriter, data interface{}, meta map[string]interface{}, statusCode int) {
+	body := map[string]interface{}{"data": data}
+	for k, v := range meta {
+		body[k] = v
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
 func SendError(w http.ResponseWriter, message string, statusCode int) {
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
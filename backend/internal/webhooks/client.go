@@ -0,0 +1,88 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when a webhook URL points at, or resolves
+// to, an address deliveries may not reach, such as loopback or a private
+// network.
+var ErrForbiddenAddress = errors.New("webhook address is not publicly routable")
+
+// AllowPrivate reports whether WEBHOOKS_ALLOW_PRIVATE is "true", which lets
+// webhooks reach localhost and private networks during local development.
+func AllowPrivate() bool {
+	return os.Getenv("WEBHOOKS_ALLOW_PRIVATE") == "true"
+}
+
+// PublicAddr reports whether ip is outside the loopback, private,
+// link-local, unspecified and multicast ranges.
+func PublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// CheckURL rejects webhook URLs whose host is localhost or a non-public IP
+// address, so that they fail when registered rather than on every delivery.
+// Hostnames are checked again by NewClient's dialer, since DNS can change.
+func CheckURL(u *url.URL, allowPrivate bool) error {
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !PublicAddr(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// NewClient returns the HTTP client deliveries are sent with. Unless
+// allowPrivate is set, it refuses to connect to non-public addresses; the
+// check runs on the resolved address, so a public hostname cannot be
+// pointed at an internal one. Redirects are returned rather than followed.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !PublicAddr(addr.Addr()) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil // A proxy would connect on our behalf, skipping the check
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,190 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"task.claimed"}`)
+	signature := Sign(testSecret, 1700000000, body)
+
+	if !Verify(testSecret, 1700000000, body, signature) {
+		t.Error("Verify rejected a valid signature")
+	}
+	if Verify("whsec_other", 1700000000, body, signature) {
+		t.Error("Verify accepted a signature made with another secret")
+	}
+	if Verify(testSecret, 1700000001, body, signature) {
+		t.Error("Verify accepted a signature for another timestamp")
+	}
+	if Verify(testSecret, 1700000000, []byte(`{"type":"task.confirmed"}`), signature) {
+		t.Error("Verify accepted a signature for another body")
+	}
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	delivery := Delivery{
+		ID:        "0b6e5c1e-6f0e-4c43-9d0e-3f1c0d2b7a11",
+		EventType: "task.claimed",
+		Secret:    testSecret,
+		Payload:   []byte(`{"type":"task.claimed"}`),
+	}
+
+	var verified bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		verified = err == nil &&
+			r.Header.Get(EventHeader) == delivery.EventType &&
+			r.Header.Get(DeliveryHeader) == delivery.ID &&
+			Verify(testSecret, timestamp, body, r.Header.Get(SignatureHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	delivery.URL = receiver.URL
+
+	status, err := Send(context.Background(), NewClient(time.Second, true), delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Send = %d, %v; want %d, nil", status, err, http.StatusNoContent)
+	}
+	if !verified {
+		t.Error("receiver could not verify the delivery's headers and signature")
+	}
+}
+
+func TestSendReportsErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	status, err := Send(context.Background(), NewClient(time.Second, true), Delivery{URL: receiver.URL})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || status != http.StatusServiceUnavailable {
+		t.Fatalf("Send = %d, %v; want %d and a *StatusError", status, err, http.StatusServiceUnavailable)
+	}
+}
+
+func TestClientDoesNotFollowRedirects(t *testing.T) {
+	var followed bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	status, err := Send(context.Background(), NewClient(time.Second, true), Delivery{URL: receiver.URL})
+	if err == nil || status != http.StatusTemporaryRedirect {
+		t.Errorf("Send = %d, %v; want %d and an error", status, err, http.StatusTemporaryRedirect)
+	}
+	if followed {
+		t.Error("client followed the redirect")
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	var reached bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer receiver.Close()
+
+	_, err := Send(context.Background(), NewClient(time.Second, false), Delivery{URL: receiver.URL})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Send error = %v, want %v", err, ErrForbiddenAddress)
+	}
+	if reached {
+		t.Error("client connected to a loopback address")
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := PublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("PublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{"https://example.com/hooks", false, false},
+		{"https://93.184.216.34/hooks", false, false},
+		{"http://localhost:8080/hooks", false, true},
+		{"http://api.localhost/hooks", false, true},
+		{"http://127.0.0.1/hooks", false, true},
+		{"http://[::1]/hooks", false, true},
+		{"http://169.254.169.254/latest/meta-data", false, true},
+		{"http://10.0.0.5/hooks", false, true},
+		{"http://localhost:8080/hooks", true, false},
+		{"http://10.0.0.5/hooks", true, false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", tt.url, err)
+		}
+		if err := CheckURL(u, tt.allowPrivate); (err != nil) != tt.wantErr {
+			t.Errorf("CheckURL(%q, %v) = %v, want error: %v", tt.url, tt.allowPrivate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{MaxAttempts, 64 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
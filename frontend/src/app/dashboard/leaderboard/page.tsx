@@ -77,7 +77,7 @@ export default function LeaderboardPage() {
 
 									<div className="flex-1">
 										<h6>{entry.name}</h6>
-										<p className="text-small text-neutral-500">{entry.credits} credits earned</p>
+										<p className="text-small text-neutral-500">{entry.score} credits</p>
 									</div>
 
 									{entry.rank <= 3 && <Badge>Top contributor</Badge>}
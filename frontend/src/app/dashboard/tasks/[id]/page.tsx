@@ -110,7 +110,7 @@ export default function TaskDetailPage({ params }: TaskDetailPageProps) {
 	const canClaim = task.status === 'open' && !isRequester;
 	const canComplete = task.status === 'claimed' && isClaimer;
 	const canConfirm = task.status === 'completed' && isRequester;
-	const canCancel = (task.status === 'claimed' || task.status === 'completed') && isRequester;
+	const canCancel = task.status === 'claimed' && isRequester;
 	const canDelete = task.status === 'open' && isRequester;
 
 	return (
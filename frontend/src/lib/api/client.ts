@@ -4,6 +4,15 @@ import type { LeaderboardEntry, Reward, Transaction, ApiError } from '../../type
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
 
+// The largest page the API serves.
+const PAGE_LIMIT = 100;
+
+interface Page<T> {
+	data: T[];
+	next_cursor: string | null;
+	has_more: boolean;
+}
+
 class ApiClient {
 	private baseUrl: string;
 
@@ -52,6 +61,24 @@ class ApiClient {
 		}
 	}
 
+	// Fetches every page of a paginated list by following next_cursor.
+	private async requestAll<T>(endpoint: string, options: RequestInit = {}): Promise<T[]> {
+		const items: T[] = [];
+		let cursor: string | null = null;
+
+		do {
+			const query: string = cursor ? `&cursor=${encodeURIComponent(cursor)}` : '';
+			const page: Page<T> = await this.request<Page<T>>(
+				`${endpoint}?limit=${PAGE_LIMIT}${query}`,
+				options
+			);
+			items.push(...(page.data ?? []));
+			cursor = page.next_cursor;
+		} while (cursor);
+
+		return items;
+	}
+
 	private getAuthHeaders(token?: string): HeadersInit {
 		if (!token) return {};
 		return {
@@ -69,7 +96,7 @@ class ApiClient {
 	}
 
 	async listTasks(): Promise<Task[]> {
-		return this.request<Task[]>('/v1/tasks');
+		return this.requestAll<Task>('/v1/tasks');
 	}
 
 	async getTask(taskId: string): Promise<Task> {
@@ -108,13 +135,13 @@ class ApiClient {
 	}
 
 	async getMyPostedTasks(token: string): Promise<Task[]> {
-		return this.request<Task[]>('/v1/tasks/my-posted', {
+		return this.requestAll<Task>('/v1/tasks/my-posted', {
 			headers: this.getAuthHeaders(token),
 		});
 	}
 
 	async getMyClaimedTasks(token: string): Promise<Task[]> {
-		return this.request<Task[]>('/v1/tasks/my-claimed', {
+		return this.requestAll<Task>('/v1/tasks/my-claimed', {
 			headers: this.getAuthHeaders(token),
 		});
 	}
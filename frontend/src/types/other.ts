@@ -2,7 +2,7 @@ export interface LeaderboardEntry {
 	id: string;
 	name: string;
 	avatar_url?: string;
-	credits: number;
+	score: number;
 	rank: number;
 }
 